@@ -0,0 +1,61 @@
+package cache
+
+import (
+	"sync"
+)
+
+// InvalidationMessage notifies other instances that a key should be dropped from their private layers
+type InvalidationMessage struct {
+	Origin string
+	Key    string
+}
+
+// InvalidationBus is the pub/sub mechanism used to broadcast invalidations between MultiLevelCache instances
+type InvalidationBus interface {
+	Publish(message InvalidationMessage) error
+	Subscribe(handler func(InvalidationMessage)) (unsubscribe func())
+}
+
+// LocalInvalidationBus is an in-process InvalidationBus, useful to share invalidations between caches living
+// in the same process and as a stand-in for a real pub/sub in tests
+type LocalInvalidationBus struct {
+	handlers map[int]func(InvalidationMessage)
+	nextID   int
+	mutex    sync.RWMutex
+}
+
+// NewLocalInvalidationBus constructs a new in-process invalidation bus
+func NewLocalInvalidationBus() *LocalInvalidationBus {
+	return &LocalInvalidationBus{handlers: make(map[int]func(InvalidationMessage))}
+}
+
+// Publish synchronously delivers the message to every subscriber
+func (b *LocalInvalidationBus) Publish(message InvalidationMessage) error {
+	b.mutex.RLock()
+	handlers := make([]func(InvalidationMessage), 0, len(b.handlers))
+	for _, handler := range b.handlers {
+		handlers = append(handlers, handler)
+	}
+	b.mutex.RUnlock()
+
+	for _, handler := range handlers {
+		handler(message)
+	}
+	return nil
+}
+
+// Subscribe registers a handler for incoming messages, and returns a function to remove it
+func (b *LocalInvalidationBus) Subscribe(handler func(InvalidationMessage)) func() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	id := b.nextID
+	b.nextID++
+	b.handlers[id] = handler
+	return func() {
+		b.mutex.Lock()
+		defer b.mutex.Unlock()
+		delete(b.handlers, id)
+	}
+}
+
+var _ InvalidationBus = (*LocalInvalidationBus)(nil)
@@ -0,0 +1,71 @@
+// Package layers contains ready-to-use MultiLevelCache layers
+package layers
+
+import (
+	"context"
+	"time"
+
+	"github.com/splitio/go-toolkit/v5/datastructures/cache"
+)
+
+// LocalStore is the subset of in-memory cache operations needed to use it as a layer.
+// It's implemented by both cache.LocalCacheImpl and cache.ShardedLocalCacheImpl
+type LocalStore interface {
+	cache.LocalCache
+	Delete(key string) bool
+}
+
+// LocalStoreWithTTL is implemented by in-memory caches that accept a custom TTL on each write, such as
+// cache.LocalCacheImpl
+type LocalStoreWithTTL interface {
+	LocalStore
+	SetWithTTL(key string, value interface{}, ttl time.Duration) error
+}
+
+// LocalLayer adapts an in-memory cache to be used as a MultiLevelCache layer
+type LocalLayer struct {
+	store LocalStore
+}
+
+// NewLocalLayer constructs a new layer wrapping an in-memory cache. If the cache supports custom TTLs, a
+// *LocalTTLLayer is returned, which makes it eligible for negative entries & per-layer TTLs.
+// Otherwise a *LocalLayer is returned, and writes always use the cache's default TTL
+func NewLocalLayer(store LocalStore) cache.MLCLayer {
+	if withTTL, ok := store.(LocalStoreWithTTL); ok {
+		return &LocalTTLLayer{LocalLayer: LocalLayer{store: store}, store: withTTL}
+	}
+	return &LocalLayer{store: store}
+}
+
+// Get returns the value or the *cache.Miss / *cache.Expired error reported by the underlying cache
+func (l *LocalLayer) Get(ctx context.Context, key string) (interface{}, error) {
+	return l.store.Get(key)
+}
+
+// Set stores the value
+func (l *LocalLayer) Set(ctx context.Context, key string, value interface{}) error {
+	return l.store.Set(key, value)
+}
+
+// Delete removes the key
+func (l *LocalLayer) Delete(ctx context.Context, key string) error {
+	l.store.Delete(key)
+	return nil
+}
+
+// LocalTTLLayer adapts an in-memory cache with custom TTL support to be used as a MultiLevelCache layer
+type LocalTTLLayer struct {
+	LocalLayer
+	store LocalStoreWithTTL
+}
+
+// SetWithTTL stores the value with a custom TTL
+func (l *LocalTTLLayer) SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	return l.store.SetWithTTL(key, value, ttl)
+}
+
+var _ cache.MLCLayer = (*LocalLayer)(nil)
+var _ cache.MLCLayerWithTTL = (*LocalTTLLayer)(nil)
+var _ LocalStoreWithTTL = (*cache.LocalCacheImpl)(nil)
+var _ LocalStore = (*cache.LocalCacheImpl)(nil)
+var _ LocalStore = (*cache.ShardedLocalCacheImpl)(nil)
@@ -0,0 +1,101 @@
+package layers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/splitio/go-toolkit/v5/datastructures/cache"
+)
+
+func TestLocalLayer(t *testing.T) {
+	local, _ := cache.NewLocalCache(10, time.Minute)
+	remote := make(map[string]interface{})
+	bottom := &mapLayer{items: remote}
+	layer := NewLocalLayer(local)
+
+	remote["key1"] = "value1"
+	cacheML, _ := cache.NewMultiLevel([]cache.MLCLayer{layer, bottom}, nil)
+	if value, err := cacheML.Get(context.TODO(), "key1"); err != nil || value != "value1" {
+		t.Error("value should have been fetched from the lower layer. Got: ", value, err)
+	}
+	if value, _ := local.Get("key1"); value != "value1" {
+		t.Error("local layer should have been backfilled. Got: ", value)
+	}
+
+	if err := cacheML.Delete(context.TODO(), "key1"); err != nil {
+		t.Error("No error should have been returned. Got: ", err)
+	}
+	var asMiss *cache.Miss
+	if _, err := layer.Get(context.TODO(), "key1"); !errors.As(err, &asMiss) {
+		t.Error("key should have been removed from the local layer. Got: ", err)
+	}
+}
+
+type mapLayer struct {
+	items map[string]interface{}
+}
+
+func (m *mapLayer) Get(ctx context.Context, key string) (interface{}, error) {
+	if value, ok := m.items[key]; ok {
+		return value, nil
+	}
+	return nil, &cache.Miss{Where: "MAP", Key: key}
+}
+
+func (m *mapLayer) Set(ctx context.Context, key string, value interface{}) error {
+	m.items[key] = value
+	return nil
+}
+
+func (m *mapLayer) Delete(ctx context.Context, key string) error {
+	delete(m.items, key)
+	return nil
+}
+
+func TestLocalLayerTTL(t *testing.T) {
+	local, _ := cache.NewLocalCache(10, time.Minute)
+	layer, ok := NewLocalLayer(local).(cache.MLCLayerWithTTL)
+	if !ok {
+		t.Fatal("stores supporting custom ttls should be wrapped in a layer exposing them")
+	}
+	layer.SetWithTTL(context.TODO(), "key1", "value1", 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	var asExpired *cache.Expired
+	if _, err := layer.Get(context.TODO(), "key1"); !errors.As(err, &asExpired) {
+		t.Error("custom ttl should have been applied. Got: ", err)
+	}
+}
+
+func TestLocalLayerWithoutTTLSupport(t *testing.T) {
+	sharded, _ := cache.NewShardedLocalCache(10, time.Minute, 2)
+	layer := NewLocalLayer(sharded)
+	if _, ok := layer.(cache.MLCLayerWithTTL); ok {
+		t.Error("stores without custom ttl support should not expose SetWithTTL")
+	}
+
+	remote := make(map[string]interface{})
+	cacheML, _ := cache.NewMultiLevelWithOptions(
+		[]cache.MLCLayer{layer, &mapLayer{items: remote}},
+		&cache.MultiLevelCacheOptions{NegativeTTL: time.Hour, LayerTTLs: []time.Duration{time.Millisecond}},
+		nil,
+	)
+
+	var asMiss *cache.Miss
+	if _, err := cacheML.Get(context.TODO(), "key1"); !errors.As(err, &asMiss) {
+		t.Error("key should be missing. Got: ", err)
+	}
+	if _, err := sharded.Get("key1"); !errors.As(err, &asMiss) {
+		t.Error("no negative entry should be stored in a layer that can't expire it early. Got: ", err)
+	}
+
+	remote["key1"] = "value1"
+	if value, err := cacheML.Get(context.TODO(), "key1"); err != nil || value != "value1" {
+		t.Error("value should be fetched from the lower layer. Got: ", value, err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if value, err := sharded.Get("key1"); err != nil || value != "value1" {
+		t.Error("layer ttl can't be applied, so the store's default should be used. Got: ", value, err)
+	}
+}
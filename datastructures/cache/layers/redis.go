@@ -0,0 +1,87 @@
+package layers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/splitio/go-toolkit/v5/datastructures/cache"
+	"github.com/splitio/go-toolkit/v5/redis"
+)
+
+// negativeMarker is stored in place of cache.NegativeEntry, which cannot go through the serializer
+const negativeMarker = "\x00__negative__"
+
+// RedisLayerOptions contains the parameters used to customize a RedisLayer
+type RedisLayerOptions struct {
+	// Serializer used to store values. Defaults to JSON
+	Serializer Serializer
+	// TTL applied on every write. 0 means keys don't expire
+	TTL time.Duration
+}
+
+// RedisLayer is a MultiLevelCache layer backed by redis. Keys are prefixed according to the supplied client, and
+// the context passed to every operation reaches redis
+type RedisLayer struct {
+	client     *redis.PrefixedRedisClient
+	serializer Serializer
+	ttl        time.Duration
+}
+
+// NewRedisLayer constructs a new redis-backed cache layer
+func NewRedisLayer(client *redis.PrefixedRedisClient, options *RedisLayerOptions) (*RedisLayer, error) {
+	if client == nil {
+		return nil, errors.New("a redis client is required")
+	}
+	if options == nil {
+		options = &RedisLayerOptions{}
+	}
+
+	serializer := options.Serializer
+	if serializer == nil {
+		serializer = JSONSerializer[interface{}]{}
+	}
+
+	return &RedisLayer{client: client, serializer: serializer, ttl: options.TTL}, nil
+}
+
+// Get fetches & deserializes the value, returning a *cache.Miss if the key doesn't exist
+func (l *RedisLayer) Get(ctx context.Context, key string) (interface{}, error) {
+	raw, err := l.client.WithContext(ctx).Get(key)
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, &cache.Miss{Where: "REDIS", Key: key}
+		}
+		return nil, err
+	}
+	if raw == negativeMarker {
+		return cache.NegativeEntry{}, nil
+	}
+	return l.serializer.Deserialize([]byte(raw))
+}
+
+// Set serializes & stores the value with the configured TTL
+func (l *RedisLayer) Set(ctx context.Context, key string, value interface{}) error {
+	return l.SetWithTTL(ctx, key, value, l.ttl)
+}
+
+// SetWithTTL serializes & stores the value with a custom TTL
+func (l *RedisLayer) SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	if _, ok := value.(cache.NegativeEntry); ok {
+		return l.client.WithContext(ctx).Set(key, negativeMarker, ttl)
+	}
+
+	data, err := l.serializer.Serialize(value)
+	if err != nil {
+		return err
+	}
+	return l.client.WithContext(ctx).Set(key, data, ttl)
+}
+
+// Delete removes the key
+func (l *RedisLayer) Delete(ctx context.Context, key string) error {
+	_, err := l.client.WithContext(ctx).Del(key)
+	return err
+}
+
+var _ cache.MLCLayerWithTTL = (*RedisLayer)(nil)
@@ -0,0 +1,143 @@
+package layers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/splitio/go-toolkit/v5/datastructures/cache"
+	"github.com/splitio/go-toolkit/v5/redis"
+	"github.com/splitio/go-toolkit/v5/redis/mocks"
+)
+
+type someStruct struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func TestRedisLayer(t *testing.T) {
+	stored := make(map[string]interface{})
+	var ttl time.Duration
+	client := &mocks.MockClient{
+		GetCall: func(key string) redis.Result {
+			value, ok := stored[key]
+			return &mocks.MockResultOutput{ResultStringCall: func() (string, error) {
+				if !ok {
+					return "", redis.Nil
+				}
+				if asBytes, ok := value.([]byte); ok {
+					return string(asBytes), nil
+				}
+				return value.(string), nil
+			}}
+		},
+		SetCall: func(key string, value interface{}, expiration time.Duration) redis.Result {
+			stored[key] = value
+			ttl = expiration
+			return &mocks.MockResultOutput{ErrCall: func() error { return nil }}
+		},
+		DelCall: func(keys ...string) redis.Result {
+			for _, key := range keys {
+				delete(stored, key)
+			}
+			return &mocks.MockResultOutput{ResultCall: func() (int64, error) { return int64(len(keys)), nil }}
+		},
+	}
+
+	var contexts []context.Context
+	client.WithContextCall = func(ctx context.Context) redis.Client {
+		contexts = append(contexts, ctx)
+		return client
+	}
+
+	prefixed, _ := redis.NewPrefixedRedisClient(client, "prefix")
+	layer, err := NewRedisLayer(prefixed, &RedisLayerOptions{Serializer: JSONSerializer[someStruct]{}, TTL: time.Minute})
+	if err != nil {
+		t.Error("No error should have been returned. Got: ", err)
+	}
+
+	if err := layer.Set(context.TODO(), "key1", someStruct{Name: "a", Count: 1}); err != nil {
+		t.Error("No error should have been returned. Got: ", err)
+	}
+	if string(stored["prefix.key1"].([]byte)) != `{"name":"a","count":1}` || ttl != time.Minute {
+		t.Error("value should have been serialized & stored with prefix and ttl. Got: ", stored, ttl)
+	}
+
+	value, err := layer.Get(context.TODO(), "key1")
+	if err != nil || value != (someStruct{Name: "a", Count: 1}) {
+		t.Error("value should have been deserialized. Got: ", value, err)
+	}
+
+	if err := layer.Delete(context.TODO(), "key1"); err != nil || len(stored) != 0 {
+		t.Error("key should have been deleted. Got: ", err)
+	}
+
+	if err := layer.SetWithTTL(context.TODO(), "key2", cache.NegativeEntry{}, time.Second); err != nil || ttl != time.Second {
+		t.Error("negative entry should have been stored with the supplied ttl. Got: ", err, ttl)
+	}
+	if value, err := layer.Get(context.TODO(), "key2"); err != nil || value != (cache.NegativeEntry{}) {
+		t.Error("negative entry should be read back. Got: ", value, err)
+	}
+	layer.Delete(context.TODO(), "key2")
+
+	_, err = layer.Get(context.TODO(), "key1")
+	var asMiss *cache.Miss
+	if !errors.As(err, &asMiss) || asMiss.Key != "key1" || asMiss.Where != "REDIS" {
+		t.Errorf("Error should be a Miss. Is %+v", err)
+	}
+
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "someValue")
+	contexts = nil
+	layer.Set(ctx, "key3", someStruct{})
+	layer.Get(ctx, "key3")
+	layer.Delete(ctx, "key3")
+	if len(contexts) != 3 {
+		t.Fatal("every operation should be bound to the supplied context. Got: ", len(contexts))
+	}
+	for _, used := range contexts {
+		if used.Value(ctxKey{}) != "someValue" {
+			t.Error("the supplied context should reach redis")
+		}
+	}
+}
+
+func TestRedisLayerErrors(t *testing.T) {
+	if _, err := NewRedisLayer(nil, nil); err == nil {
+		t.Error("a client should be required")
+	}
+
+	client := &mocks.MockClient{
+		GetCall: func(key string) redis.Result {
+			return &mocks.MockResultOutput{ResultStringCall: func() (string, error) { return "", errors.New("someError") }}
+		},
+	}
+	client.WithContextCall = func(ctx context.Context) redis.Client { return client }
+	prefixed, _ := redis.NewPrefixedRedisClient(client, "")
+	layer, _ := NewRedisLayer(prefixed, &RedisLayerOptions{Serializer: StringSerializer{}})
+
+	var asMiss *cache.Miss
+	if _, err := layer.Get(context.TODO(), "key1"); err == nil || errors.As(err, &asMiss) {
+		t.Error("redis errors should be propagated as-is. Got: ", err)
+	}
+	if err := layer.Set(context.TODO(), "key1", 123); err == nil {
+		t.Error("serialization errors should be propagated")
+	}
+}
+
+func TestSerializers(t *testing.T) {
+	data, _ := StringSerializer{}.Serialize([]byte("someValue"))
+	if value, _ := (StringSerializer{}).Deserialize(data); value != "someValue" {
+		t.Error("wrong string round-trip. Got: ", value)
+	}
+
+	data, _ = JSONSerializer[interface{}]{}.Serialize(map[string]int{"a": 1})
+	value, err := JSONSerializer[interface{}]{}.Deserialize(data)
+	if asMap, ok := value.(map[string]interface{}); err != nil || !ok || asMap["a"] != float64(1) {
+		t.Error("wrong json round-trip. Got: ", value, err)
+	}
+	if _, err := (JSONSerializer[someStruct]{}).Deserialize([]byte("{")); err == nil {
+		t.Error("invalid json should fail")
+	}
+}
@@ -0,0 +1,51 @@
+package layers
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Serializer converts values to & from the representation stored in remote layers
+type Serializer interface {
+	Serialize(value interface{}) ([]byte, error)
+	Deserialize(data []byte) (interface{}, error)
+}
+
+// JSONSerializer stores values as JSON, decoding them into T
+type JSONSerializer[T any] struct{}
+
+// Serialize encodes the value as JSON
+func (JSONSerializer[T]) Serialize(value interface{}) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+// Deserialize decodes a JSON document into a T
+func (JSONSerializer[T]) Deserialize(data []byte) (interface{}, error) {
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// StringSerializer stores strings & byte slices as-is, and reads them back as strings
+type StringSerializer struct{}
+
+// Serialize accepts strings & byte slices only
+func (StringSerializer) Serialize(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case string:
+		return []byte(v), nil
+	case []byte:
+		return v, nil
+	}
+	return nil, fmt.Errorf("unsupported type for string serialization: %T", value)
+}
+
+// Deserialize returns the data as a string
+func (StringSerializer) Deserialize(data []byte) (interface{}, error) {
+	return string(data), nil
+}
+
+var _ Serializer = JSONSerializer[interface{}]{}
+var _ Serializer = StringSerializer{}
@@ -0,0 +1,157 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/splitio/go-toolkit/v5/logging"
+)
+
+// Loader fetches the value of a key that is missing from the cache or needs to be refreshed
+type Loader func(ctx context.Context, key string) (interface{}, error)
+
+// LoadingCache is a cache that fetches missing values by itself
+type LoadingCache interface {
+	Get(ctx context.Context, key string) (interface{}, error)
+}
+
+// LoadingCacheOptions contains the parameters used to customize a LoadingCacheImpl
+type LoadingCacheOptions struct {
+	// StaleWhileRevalidate enables serving expired values while they're reloaded in the background
+	StaleWhileRevalidate bool
+	// RefreshAhead triggers a background reload of values accessed when they're closer to expiring than this. 0 disables it
+	RefreshAhead time.Duration
+	// LoadTimeout bounds every call to the loader. Loads are shared by all callers of the same key, so they don't
+	// use any caller's context. 0 disables it
+	LoadTimeout time.Duration
+}
+
+type inflightLoad struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+}
+
+// LoadingCacheImpl wraps a LocalCacheImpl, loading missing values with the supplied loader. Concurrent loads
+// of the same key are deduplicated, so that only one of them reaches the loader and the rest wait for its result
+type LoadingCacheImpl struct {
+	cache                *LocalCacheImpl
+	loader               Loader
+	staleWhileRevalidate bool
+	refreshAhead         time.Duration
+	loadTimeout          time.Duration
+	inflight             map[string]*inflightLoad
+	mutex                sync.Mutex
+	logger               logging.LoggerInterface
+}
+
+// NewLoadingCache constructs a new loading cache on top of a local cache
+func NewLoadingCache(cache *LocalCacheImpl, loader Loader, options *LoadingCacheOptions, logger logging.LoggerInterface) (*LoadingCacheImpl, error) {
+	if cache == nil || loader == nil {
+		return nil, errors.New("cache and loader are required")
+	}
+	if options == nil {
+		options = &LoadingCacheOptions{}
+	}
+	if logger == nil {
+		logger = logging.NewLogger(nil)
+	}
+
+	return &LoadingCacheImpl{
+		cache:                cache,
+		loader:               loader,
+		staleWhileRevalidate: options.StaleWhileRevalidate,
+		refreshAhead:         options.RefreshAhead,
+		loadTimeout:          options.LoadTimeout,
+		inflight:             make(map[string]*inflightLoad),
+		logger:               logger,
+	}, nil
+}
+
+// Get returns the cached value if present, loading it otherwise. Expired values are returned (and reloaded
+// in the background) only if stale-while-revalidate is enabled
+func (c *LoadingCacheImpl) Get(ctx context.Context, key string) (interface{}, error) {
+	value, err := c.cache.Get(key)
+	if err == nil {
+		if c.refreshAhead > 0 {
+			if expiration, ok := c.cache.expiration(key); ok && time.Until(expiration) < c.refreshAhead {
+				c.refresh(key)
+			}
+		}
+		return value, nil
+	}
+
+	var expired *Expired
+	if c.staleWhileRevalidate && errors.As(err, &expired) {
+		c.refresh(key)
+		return expired.Value, nil
+	}
+
+	return c.load(ctx, key)
+}
+
+// load fetches the value, or waits for an ongoing load of the same key to finish. The load itself runs in the
+// background, so that cancelling the caller that started it doesn't fail the rest of the callers waiting for it
+func (c *LoadingCacheImpl) load(ctx context.Context, key string) (interface{}, error) {
+	call, leader := c.begin(key)
+	if leader {
+		go c.run(key, call)
+	}
+
+	select {
+	case <-call.done:
+		return call.value, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// refresh reloads the value in the background, unless a load of the same key is already in progress
+func (c *LoadingCacheImpl) refresh(key string) {
+	if call, leader := c.begin(key); leader {
+		go c.run(key, call)
+	}
+}
+
+func (c *LoadingCacheImpl) begin(key string) (*inflightLoad, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if call, ok := c.inflight[key]; ok {
+		return call, false
+	}
+
+	call := &inflightLoad{done: make(chan struct{})}
+	c.inflight[key] = call
+	return call, true
+}
+
+func (c *LoadingCacheImpl) run(key string, call *inflightLoad) {
+	ctx := context.Background()
+	if c.loadTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.loadTimeout)
+		defer cancel()
+	}
+
+	defer func() {
+		c.mutex.Lock()
+		delete(c.inflight, key)
+		c.mutex.Unlock()
+		close(call.done)
+	}()
+
+	call.value, call.err = c.loader(ctx, key)
+	if call.err != nil {
+		c.logger.Error(fmt.Sprintf("error loading key %s: %s", key, call.err.Error()))
+		return
+	}
+
+	if err := c.cache.Set(key, call.value); err != nil {
+		c.logger.Error(fmt.Sprintf("error caching loaded key %s: %s", key, err.Error()))
+	}
+}
+
+var _ LoadingCache = (*LoadingCacheImpl)(nil)
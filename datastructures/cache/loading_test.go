@@ -0,0 +1,190 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLoadingCacheSingleFlight(t *testing.T) {
+	local, _ := NewLocalCache(10, time.Minute)
+	var calls int64
+	release := make(chan struct{})
+	loader := func(ctx context.Context, key string) (interface{}, error) {
+		atomic.AddInt64(&calls, 1)
+		<-release
+		return key + "_value", nil
+	}
+
+	cache, err := NewLoadingCache(local, loader, nil, nil)
+	if err != nil {
+		t.Error("No error should have been returned. Got: ", err)
+	}
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if val, err := cache.Get(context.Background(), "someKey"); err != nil || val != "someKey_value" {
+				t.Error("unexpected result: ", val, err)
+			}
+		}()
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if c := atomic.LoadInt64(&calls); c != 1 {
+		t.Error("loader should have been called once. Was: ", c)
+	}
+	if val, _ := local.Get("someKey"); val != "someKey_value" {
+		t.Error("loaded value should have been cached. Got: ", val)
+	}
+}
+
+func TestLoadingCacheErrors(t *testing.T) {
+	if _, err := NewLoadingCache(nil, nil, nil, nil); err == nil {
+		t.Error("cache & loader should be required")
+	}
+
+	local, _ := NewLocalCache(10, time.Minute)
+	cache, _ := NewLoadingCache(local, func(ctx context.Context, key string) (interface{}, error) {
+		return nil, errors.New("someError")
+	}, nil, nil)
+
+	if _, err := cache.Get(context.Background(), "someKey"); err == nil || err.Error() != "someError" {
+		t.Error("loader error should be propagated. Got: ", err)
+	}
+	if _, err := local.Get("someKey"); err == nil {
+		t.Error("failed loads should not be cached")
+	}
+}
+
+func TestLoadingCacheContext(t *testing.T) {
+	local, _ := NewLocalCache(10, time.Minute)
+	release := make(chan struct{})
+	defer close(release)
+	cache, _ := NewLoadingCache(local, func(ctx context.Context, key string) (interface{}, error) {
+		<-release
+		return 1, nil
+	}, nil, nil)
+
+	go cache.Get(context.Background(), "someKey")
+	time.Sleep(20 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := cache.Get(ctx, "someKey"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Error("waiting for an ongoing load should honor the context. Got: ", err)
+	}
+}
+
+func TestLoadingCacheLeaderCancellation(t *testing.T) {
+	local, _ := NewLocalCache(10, time.Minute)
+	release := make(chan struct{})
+	cache, _ := NewLoadingCache(local, func(ctx context.Context, key string) (interface{}, error) {
+		select {
+		case <-release:
+			return "value", nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}, nil, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := cache.Get(ctx, "someKey")
+		leaderErr <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	followerResult := make(chan interface{}, 1)
+	go func() {
+		val, err := cache.Get(context.Background(), "someKey")
+		if err != nil {
+			t.Error("follower should not be affected by the leader's cancellation. Got: ", err)
+		}
+		followerResult <- val
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	cancel()
+	if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Error("leader should honor its own context. Got: ", err)
+	}
+
+	close(release)
+	if val := <-followerResult; val != "value" {
+		t.Error("follower should get the loaded value. Got: ", val)
+	}
+}
+
+func TestLoadingCacheLoadTimeout(t *testing.T) {
+	local, _ := NewLocalCache(10, time.Minute)
+	cache, _ := NewLoadingCache(local, func(ctx context.Context, key string) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}, &LoadingCacheOptions{LoadTimeout: 20 * time.Millisecond}, nil)
+
+	if _, err := cache.Get(context.Background(), "someKey"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Error("loads should be bounded by the load timeout. Got: ", err)
+	}
+}
+
+func TestLoadingCacheStaleWhileRevalidate(t *testing.T) {
+	local, _ := NewLocalCache(10, 60*time.Millisecond)
+	var calls int64
+	cache, _ := NewLoadingCache(local, func(ctx context.Context, key string) (interface{}, error) {
+		time.Sleep(20 * time.Millisecond)
+		return atomic.AddInt64(&calls, 1), nil
+	}, &LoadingCacheOptions{StaleWhileRevalidate: true}, nil)
+
+	if val, _ := cache.Get(context.Background(), "someKey"); val != int64(1) {
+		t.Error("first get should load synchronously. Got: ", val)
+	}
+
+	time.Sleep(80 * time.Millisecond)
+	if val, err := cache.Get(context.Background(), "someKey"); err != nil || val != int64(1) {
+		t.Error("expired value should be served while revalidating. Got: ", val, err)
+	}
+
+	time.Sleep(40 * time.Millisecond)
+	if val, err := local.Get("someKey"); err != nil || val != int64(2) {
+		t.Error("value should have been reloaded in the background. Got: ", val, err)
+	}
+}
+
+func TestLoadingCacheRefreshAhead(t *testing.T) {
+	local, _ := NewLocalCache(10, 100*time.Millisecond)
+	var calls int64
+	cache, _ := NewLoadingCache(local, func(ctx context.Context, key string) (interface{}, error) {
+		return atomic.AddInt64(&calls, 1), nil
+	}, &LoadingCacheOptions{RefreshAhead: 50 * time.Millisecond}, nil)
+
+	cache.Get(context.Background(), "someKey")
+	cache.Get(context.Background(), "someKey")
+	if c := atomic.LoadInt64(&calls); c != 1 {
+		t.Error("fresh values should not be refreshed. Calls: ", c)
+	}
+
+	time.Sleep(70 * time.Millisecond)
+	if val, _ := cache.Get(context.Background(), "someKey"); val != int64(1) {
+		t.Error("the current value should be returned while refreshing. Got: ", val)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if c := atomic.LoadInt64(&calls); c != 2 {
+		t.Error("value close to expiring should have been refreshed. Calls: ", c)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if val, err := local.Get("someKey"); err != nil || val != int64(2) {
+		t.Error("refreshed value should have a new ttl. Got: ", val, err)
+	}
+}
@@ -2,11 +2,38 @@ package cache
 
 import (
 	"container/list"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
+
+	"github.com/splitio/go-toolkit/v5/struct/traits/lifecycle"
 )
 
+// ErrEntryTooLarge is returned when a single entry exceeds the maximum size in bytes of the cache
+var ErrEntryTooLarge = errors.New("entry exceeds the cache maximum size in bytes")
+
+// Sizer returns the approximate size in bytes of a cache entry
+type Sizer func(key string, value interface{}) int64
+
+// LocalCacheOptions contains the parameters used to customize a LocalCacheImpl
+type LocalCacheOptions struct {
+	// MaxSize is the maximum number of entries. Must be higher than 0
+	MaxSize int
+	// TTL is the time after which an entry is considered expired
+	TTL time.Duration
+	// MaxBytes is the maximum approximate size of all entries as computed by Sizer. 0 disables the limit
+	MaxBytes int64
+	// Sizer computes the size of every entry. Required if MaxBytes is set
+	Sizer Sizer
+	// SweepInterval is the period with which the janitor removes expired entries once started. Required to start it
+	SweepInterval time.Duration
+	// Policy selects the entries to evict when the cache is full. Defaults to LRU
+	Policy EvictionPolicy
+	// OnEvict is called every time an entry leaves the cache. It's invoked once the cache lock has been released
+	OnEvict func(key string, value interface{}, reason EvictionReason)
+}
+
 // LocalCache is an in-memory TTL & LRU cache
 type LocalCache interface {
 	Get(key string) (interface{}, error)
@@ -15,17 +42,32 @@ type LocalCache interface {
 
 // LocalCacheImpl implements the LocalCache interface
 type LocalCacheImpl struct {
-	ttl    time.Duration
-	maxLen int
-	ttls   map[string]time.Time
-	items  map[string]*list.Element
-	lru    *list.List
-	mutex  sync.Mutex
+	ttl           time.Duration
+	maxLen        int
+	maxBytes      int64
+	bytes         int64
+	sizer         Sizer
+	sweepInterval time.Duration
+	janitor       lifecycle.Manager
+	policy        EvictionPolicy
+	onEvict       func(key string, value interface{}, reason EvictionReason)
+	evicted       []eviction
+	stats         localStats
+	ttls          map[string]time.Time
+	items         map[string]*list.Element
+	lru           *list.List
+	mutex         sync.Mutex
 }
 
 type entry struct {
 	key   string
 	value interface{}
+	size  int64
+}
+
+type eviction struct {
+	entry  entry
+	reason EvictionReason
 }
 
 // Get retrieves an item if exist, nil + an error otherwise
@@ -34,6 +76,7 @@ func (c *LocalCacheImpl) Get(key string) (interface{}, error) {
 	defer c.mutex.Unlock()
 	node, ok := c.items[key]
 	if !ok {
+		c.stats.misses++
 		return nil, &Miss{Where: "LOCAL", Key: key}
 	}
 
@@ -52,51 +95,231 @@ func (c *LocalCacheImpl) Get(key string) (interface{}, error) {
 	}
 
 	if time.Now().UnixNano() > ttl.UnixNano() {
+		c.stats.expirations++
 		return nil, &Expired{Key: key, Value: entry.value, When: ttl.Add(c.ttl)}
 	}
 
-	c.lru.MoveToFront(node)
+	c.stats.hits++
+	c.touch(node, entry.key)
 	return entry.value, nil
 }
 
-// Set adds a new item. Since the cache being full results in removing the LRU element, this method never fails.
+// Set adds a new item. Since the cache being full results in removing the LRU element(s), this method only fails
+// if a size limit in bytes is set and the item alone exceeds it.
 func (c *LocalCacheImpl) Set(key string, value interface{}) error {
+	return c.SetWithTTL(key, value, c.ttl)
+}
+
+// SetWithTTL adds a new item that expires after the supplied ttl instead of the default one
+func (c *LocalCacheImpl) SetWithTTL(key string, value interface{}, ttl time.Duration) error {
 	c.mutex.Lock()
-	defer c.mutex.Unlock()
+	defer c.unlock()
+
+	var size int64
+	if c.sizer != nil {
+		size = c.sizer(key, value)
+	}
+	if c.maxBytes > 0 && size > c.maxBytes {
+		if node, ok := c.items[key]; ok {
+			c.removeElement(node, EvictionCapacity)
+		}
+		return ErrEntryTooLarge
+	}
+
 	if node, ok := c.items[key]; ok {
-		c.lru.MoveToFront(node)
-		node.Value = entry{key: key, value: value}
+		c.touch(node, key)
+		if old, ok := node.Value.(entry); ok {
+			c.bytes -= old.size
+		}
+		node.Value = entry{key: key, value: value, size: size}
 	} else {
-		// Drop the LRU item on the list before adding a new one.
+		// Drop an item (the LRU one unless a policy is set) before adding a new one.
 		if c.lru.Len() == c.maxLen {
-			entry, ok := c.lru.Back().Value.(entry)
-			if !ok {
-				return fmt.Errorf("Invalid data in list for key %s", key)
+			if err := c.removeElement(c.victim(key), EvictionCapacity); err != nil {
+				return err
 			}
-			key := entry.key
-			delete(c.items, key)
-			delete(c.ttls, key)
-			c.lru.Remove(c.lru.Back())
 		}
 
-		ptr := c.lru.PushFront(entry{key: key, value: value})
+		ptr := c.lru.PushFront(entry{key: key, value: value, size: size})
 		c.items[key] = ptr
+		if c.policy != nil {
+			c.policy.Added(key)
+		}
+	}
+	c.bytes += size
+	c.ttls[key] = time.Now().Add(ttl)
+
+	// Drop LRU items until the size in bytes is within bounds
+	for c.maxBytes > 0 && c.bytes > c.maxBytes {
+		if err := c.removeElement(c.victim(""), EvictionCapacity); err != nil {
+			return err
+		}
 	}
-	c.ttls[key] = time.Now().Add(c.ttl)
 	return nil
 }
 
+// Delete removes an item, returning true if it was present
+func (c *LocalCacheImpl) Delete(key string) bool {
+	c.mutex.Lock()
+	defer c.unlock()
+	node, ok := c.items[key]
+	if ok {
+		c.removeElement(node, EvictionDeleted)
+	}
+	return ok
+}
+
+// Sweep removes all expired entries and returns how many were removed
+func (c *LocalCacheImpl) Sweep() int {
+	c.mutex.Lock()
+	defer c.unlock()
+
+	now := time.Now().UnixNano()
+	removed := 0
+	for key, ttl := range c.ttls {
+		if now > ttl.UnixNano() {
+			if node, ok := c.items[key]; ok {
+				c.removeElement(node, EvictionExpired)
+				removed++
+			}
+		}
+	}
+	return removed
+}
+
+// StartJanitor starts a goroutine that periodically sweeps expired entries
+func (c *LocalCacheImpl) StartJanitor() error {
+	if c.sweepInterval <= 0 {
+		return errors.New("a sweep interval is required to start the janitor")
+	}
+
+	if !c.janitor.BeginInitialization() {
+		return errors.New("janitor is not idle")
+	}
+
+	go func() {
+		defer c.janitor.ShutdownComplete()
+		if !c.janitor.InitializationComplete() {
+			return
+		}
+
+		ticker := time.NewTicker(c.sweepInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-c.janitor.ShutdownRequested():
+				return
+			case <-ticker.C:
+				c.Sweep()
+			}
+		}
+	}()
+	return nil
+}
+
+// StopJanitor stops the janitor goroutine, optionally blocking until it's done
+func (c *LocalCacheImpl) StopJanitor(blocking bool) error {
+	if !c.janitor.BeginShutdown() {
+		return errors.New("janitor not running")
+	}
+
+	if blocking {
+		c.janitor.AwaitShutdownComplete()
+	}
+	return nil
+}
+
+// expiration returns the time at which an entry expires
+func (c *LocalCacheImpl) expiration(key string) (time.Time, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	ttl, ok := c.ttls[key]
+	return ttl, ok
+}
+
+// touch registers an access to an entry. Must be called with the lock held
+func (c *LocalCacheImpl) touch(node *list.Element, key string) {
+	if c.policy == nil {
+		c.lru.MoveToFront(node)
+		return
+	}
+	c.policy.Accessed(key)
+}
+
+// victim returns the element to evict in order to make room. Must be called with the lock held
+func (c *LocalCacheImpl) victim(candidate string) *list.Element {
+	if c.policy != nil {
+		if node, ok := c.items[c.policy.Victim(candidate)]; ok {
+			return node
+		}
+	}
+	return c.lru.Back()
+}
+
+// removeElement drops an entry from the list and the indexes. Must be called with the lock held
+func (c *LocalCacheImpl) removeElement(node *list.Element, reason EvictionReason) error {
+	entry, ok := node.Value.(entry)
+	if !ok {
+		return fmt.Errorf("Invalid data in list: %+v", node.Value)
+	}
+	delete(c.items, entry.key)
+	delete(c.ttls, entry.key)
+	c.bytes -= entry.size
+	c.lru.Remove(node)
+	if c.policy != nil {
+		c.policy.Removed(entry.key, reason)
+	}
+	if reason != EvictionDeleted {
+		c.stats.evictions++
+	}
+	if c.onEvict != nil {
+		c.evicted = append(c.evicted, eviction{entry: entry, reason: reason})
+	}
+	return nil
+}
+
+// unlock releases the lock and notifies the evictions that took place while it was held
+func (c *LocalCacheImpl) unlock() {
+	evicted := c.evicted
+	c.evicted = nil
+	c.mutex.Unlock()
+	for _, e := range evicted {
+		c.onEvict(e.entry.key, e.entry.value, e.reason)
+	}
+}
+
 // NewLocalCache returns a new LocalCache instance of the specified size and TTL
 func NewLocalCache(maxSize int, ttl time.Duration) (*LocalCacheImpl, error) {
-	if maxSize <= 0 {
-		return nil, fmt.Errorf("Cache size should be > 0. Is: %d", maxSize)
+	return NewLocalCacheWithOptions(&LocalCacheOptions{MaxSize: maxSize, TTL: ttl})
+}
+
+// NewLocalCacheWithOptions returns a new LocalCache instance with a custom setup
+func NewLocalCacheWithOptions(options *LocalCacheOptions) (*LocalCacheImpl, error) {
+	if options == nil {
+		return nil, errors.New("options cannot be nil")
+	}
+	if options.MaxSize <= 0 {
+		return nil, fmt.Errorf("Cache size should be > 0. Is: %d", options.MaxSize)
+	}
+	if options.MaxBytes < 0 {
+		return nil, fmt.Errorf("Cache size in bytes should be >= 0. Is: %d", options.MaxBytes)
+	}
+	if options.MaxBytes > 0 && options.Sizer == nil {
+		return nil, errors.New("A sizer is required when setting a size limit in bytes")
 	}
 
-	return &LocalCacheImpl{
-		maxLen: maxSize,
-		ttl:    ttl,
-		lru:    new(list.List),
-		items:  make(map[string]*list.Element, maxSize),
-		ttls:   make(map[string]time.Time),
-	}, nil
+	c := &LocalCacheImpl{
+		maxLen:        options.MaxSize,
+		ttl:           options.TTL,
+		maxBytes:      options.MaxBytes,
+		sizer:         options.Sizer,
+		sweepInterval: options.SweepInterval,
+		policy:        options.Policy,
+		onEvict:       options.OnEvict,
+		lru:           new(list.List),
+		items:         make(map[string]*list.Element, options.MaxSize),
+		ttls:          make(map[string]time.Time),
+	}
+	c.janitor.Setup()
+	return c, nil
 }
@@ -0,0 +1,147 @@
+package cache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestLocalCacheJanitor(t *testing.T) {
+	cache, err := NewLocalCacheWithOptions(&LocalCacheOptions{MaxSize: 10, TTL: 50 * time.Millisecond, SweepInterval: 20 * time.Millisecond})
+	if err != nil {
+		t.Error("No error should have been returned. Got: ", err)
+	}
+
+	for i := 1; i <= 5; i++ {
+		cache.Set(fmt.Sprintf("someKey%d", i), i)
+	}
+
+	if removed := cache.Sweep(); removed != 0 {
+		t.Error("No keys should have been swept. Got: ", removed)
+	}
+
+	if err := cache.StartJanitor(); err != nil {
+		t.Error("Janitor should start. Got: ", err)
+	}
+	if err := cache.StartJanitor(); err == nil {
+		t.Error("Janitor should not be started twice")
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	cache.mutex.Lock()
+	if len(cache.items) != 0 || len(cache.ttls) != 0 || cache.lru.Len() != 0 {
+		t.Error("All expired keys should have been swept. Remaining: ", len(cache.items))
+	}
+	cache.mutex.Unlock()
+
+	if _, err := cache.Get("someKey1"); err == nil {
+		t.Error("Swept keys should result in a miss")
+	} else if _, ok := err.(*Miss); !ok {
+		t.Errorf("Error should be of type Miss. Is %T", err)
+	}
+
+	if err := cache.StopJanitor(true); err != nil {
+		t.Error("Janitor should stop. Got: ", err)
+	}
+	if err := cache.StopJanitor(true); err == nil {
+		t.Error("Stopping an idle janitor should fail")
+	}
+
+	noInterval, _ := NewLocalCache(10, time.Second)
+	if err := noInterval.StartJanitor(); err == nil {
+		t.Error("Janitor should not start without a sweep interval")
+	}
+}
+
+func TestLocalCacheMaxBytes(t *testing.T) {
+	sizer := func(key string, value interface{}) int64 { return int64(len(key) + len(value.(string))) }
+
+	if _, err := NewLocalCacheWithOptions(&LocalCacheOptions{MaxSize: 10, MaxBytes: 10}); err == nil {
+		t.Error("A sizer should be required when setting MaxBytes")
+	}
+
+	cache, err := NewLocalCacheWithOptions(&LocalCacheOptions{MaxSize: 10, TTL: time.Minute, MaxBytes: 20, Sizer: sizer})
+	if err != nil {
+		t.Error("No error should have been returned. Got: ", err)
+	}
+
+	cache.Set("k1", "12345678") // 10 bytes
+	cache.Set("k2", "1234")     // 6 bytes
+	cache.Set("k2", "12345678") // 10 bytes, replacing
+	if cache.bytes != 20 || cache.lru.Len() != 2 {
+		t.Error("Both keys should fit. Bytes: ", cache.bytes)
+	}
+
+	cache.Set("k3", "1") // 3 bytes. k1 should be evicted
+	if _, err := cache.Get("k1"); err == nil {
+		t.Error("k1 should have been evicted")
+	}
+	if cache.bytes != 13 || cache.lru.Len() != 2 {
+		t.Error("Unexpected size after eviction. Bytes: ", cache.bytes)
+	}
+
+	if err := cache.Set("k3", "this value is way too big"); err != ErrEntryTooLarge {
+		t.Error("Entries larger than the limit should be rejected. Got: ", err)
+	}
+	if _, err := cache.Get("k3"); err == nil {
+		t.Error("k3 should have been removed")
+	}
+	if cache.bytes != 10 || cache.lru.Len() != 1 || len(cache.items) != 1 || len(cache.ttls) != 1 {
+		t.Error("Unexpected size after rejecting an entry. Bytes: ", cache.bytes)
+	}
+}
+
+func TestLocalCacheEvictionCallback(t *testing.T) {
+	evictions := make(map[string]EvictionReason)
+	var cache *LocalCacheImpl
+	cache, _ = NewLocalCacheWithOptions(&LocalCacheOptions{
+		MaxSize: 2,
+		TTL:     50 * time.Millisecond,
+		OnEvict: func(key string, value interface{}, reason EvictionReason) {
+			if value != key {
+				t.Error("evicted value should be passed. Got: ", value)
+			}
+			cache.Get(key) // the lock should be released by now
+			evictions[key] = reason
+		},
+	})
+
+	cache.Set("k1", "k1")
+	cache.Set("k2", "k2")
+	cache.Set("k3", "k3")
+	if reason, ok := evictions["k1"]; !ok || reason != EvictionCapacity {
+		t.Error("k1 should have been evicted due to capacity")
+	}
+
+	if !cache.Delete("k2") || cache.Delete("k2") {
+		t.Error("k2 should have been deleted once")
+	}
+	if reason, ok := evictions["k2"]; !ok || reason != EvictionDeleted {
+		t.Error("k2 should have been evicted due to an explicit delete")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	cache.Sweep()
+	if reason, ok := evictions["k3"]; !ok || reason != EvictionExpired {
+		t.Error("k3 should have been evicted due to expiration")
+	}
+	if len(evictions) != 3 {
+		t.Error("3 evictions should have been notified. Got: ", evictions)
+	}
+}
+
+func TestLocalCacheSetWithTTL(t *testing.T) {
+	cache, _ := NewLocalCache(10, time.Minute)
+	cache.SetWithTTL("short", 1, 10*time.Millisecond)
+	cache.Set("long", 2)
+	time.Sleep(20 * time.Millisecond)
+
+	if _, err := cache.Get("short"); err == nil {
+		t.Error("custom ttl should have been applied")
+	} else if _, ok := err.(*Expired); !ok {
+		t.Error("error should be Expired. Got: ", err)
+	}
+	if value, err := cache.Get("long"); err != nil || value != 2 {
+		t.Error("default ttl should have been applied. Got: ", value, err)
+	}
+}
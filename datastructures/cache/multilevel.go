@@ -2,6 +2,10 @@ package cache
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
 
 	"github.com/splitio/go-toolkit/v5/logging"
 )
@@ -10,26 +14,89 @@ import (
 type MLCLayer interface {
 	Get(ctx context.Context, key string) (interface{}, error)
 	Set(ctx context.Context, key string, value interface{}) error
+	Delete(ctx context.Context, key string) error
+}
+
+// MLCLayerWithTTL is implemented by layers that accept a custom TTL on each write
+type MLCLayerWithTTL interface {
+	MLCLayer
+	SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error
+}
+
+// NegativeEntry is the value stored in upper layers to remember that a key is missing from every layer
+type NegativeEntry struct{}
+
+// GetResult holds a value along with the index of the layer that served it
+type GetResult struct {
+	Value interface{}
+	Layer int
 }
 
 // MultiLevelCache bundles a list of ordered cache layers (upper -> lower)
 type MultiLevelCache interface {
 	Get(ctx context.Context, key string) (interface{}, error)
+	GetWithResult(ctx context.Context, key string) (*GetResult, error)
+	Set(ctx context.Context, key string, value interface{}) error
+	Delete(ctx context.Context, key string) error
+	Invalidate(ctx context.Context, key string) error
+}
+
+// WriteMode determines how writes are propagated across layers
+type WriteMode int
+
+// Supported write modes
+const (
+	// WriteThrough stores the value in every layer, from the lowest to the upper one
+	WriteThrough WriteMode = iota
+	// WriteAround stores the value in the lowest layer only, and drops it from the upper ones so that it's
+	// backfilled on the next read
+	WriteAround
+)
+
+// MultiLevelCacheOptions contains the parameters used to customize a MultiLevelCacheImpl
+type MultiLevelCacheOptions struct {
+	WriteMode WriteMode
+	// InvalidationBus, if set, is used to broadcast deletions & invalidations to other instances
+	InvalidationBus InvalidationBus
+	// LocalLayers is the number of upper layers private to this instance, which are cleared when another
+	// instance broadcasts an invalidation. Defaults to 1
+	LocalLayers int
+	// NegativeTTL enables caching misses in the upper layers for the given duration. Only layers implementing
+	// MLCLayerWithTTL hold negative entries, since they must expire
+	NegativeTTL time.Duration
+	// LayerTTLs holds the TTL used when writing to each layer (by index) on backfills & write-throughs.
+	// 0 (or a missing index) keeps the layer's default
+	LayerTTLs []time.Duration
 }
 
 // MultiLevelCacheImpl implements the MultiLevelCache interface
 type MultiLevelCacheImpl struct {
-	layers []MLCLayer
-	logger logging.LoggerInterface
+	layers      []MLCLayer
+	writeMode   WriteMode
+	bus         InvalidationBus
+	unsubscribe func()
+	instanceID  string
+	localLayers int
+	negativeTTL time.Duration
+	layerTTLs   []time.Duration
+	stats       *multiLevelStats
+	logger      logging.LoggerInterface
 }
 
 // Get returns the value of the requested key (if found) and populates upper levels with it
 func (c *MultiLevelCacheImpl) Get(ctx context.Context, key string) (interface{}, error) {
+	result, err := c.GetWithResult(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return result.Value, nil
+}
+
+// GetWithResult behaves like Get, but also reports which layer served the value
+func (c *MultiLevelCacheImpl) GetWithResult(ctx context.Context, key string) (*GetResult, error) {
 	toUpdate := make([]int, 0, len(c.layers))
-	var item interface{}
-	var err error
 	for index, layer := range c.layers {
-		item, err = layer.Get(ctx, key)
+		item, err := layer.Get(ctx, key)
 		if err != nil {
 			switch err.(type) {
 			case *Miss:
@@ -43,33 +110,188 @@ func (c *MultiLevelCacheImpl) Get(ctx context.Context, key string) (interface{},
 			default:
 				// Any other error implies simply skipping this layer.
 				c.logger.Error(err)
+				c.stats.layerError(index)
 			}
-		} else {
-			break
+			continue
+		}
+
+		if _, negative := item.(NegativeEntry); negative || item == nil {
+			c.stats.miss()
+			return nil, &Miss{Where: "ALL_LEVELS", Key: key}
 		}
+
+		// Update upper layers if any
+		c.stats.hit(index)
+		for _, toUpdateIndex := range toUpdate {
+			if err := c.setLayer(ctx, toUpdateIndex, key, item); err != nil {
+				c.logger.Error(err)
+				c.stats.layerError(toUpdateIndex)
+				continue
+			}
+			c.stats.backfill()
+		}
+		return &GetResult{Value: item, Layer: index}, nil
+	}
+
+	c.stats.miss()
+	if c.negativeTTL > 0 {
+		c.cacheMiss(ctx, key, toUpdate)
+	}
+	return nil, &Miss{Where: "ALL_LEVELS", Key: key}
+}
+
+// Set stores the value according to the configured write mode and broadcasts the invalidation, if configured,
+// so that other instances drop their stale copies. Failing to write to the lowest layer is reported as an error,
+// while failures in upper layers are only logged
+func (c *MultiLevelCacheImpl) Set(ctx context.Context, key string, value interface{}) error {
+	if len(c.layers) == 0 {
+		return nil
+	}
+
+	lowest := len(c.layers) - 1
+	if err := c.layers[lowest].Set(ctx, key, value); err != nil {
+		c.stats.layerError(lowest)
+		return err
+	}
+
+	for index := lowest - 1; index >= 0; index-- {
+		var err error
+		switch c.writeMode {
+		case WriteAround:
+			err = c.layers[index].Delete(ctx, key)
+		default:
+			err = c.setLayer(ctx, index, key, value)
+		}
+		if err != nil {
+			c.logger.Error(err)
+			c.stats.layerError(index)
+		}
+	}
+	c.broadcast(key)
+	return nil
+}
+
+// Delete removes the key from every layer (lowest first) and broadcasts the invalidation, if configured
+func (c *MultiLevelCacheImpl) Delete(ctx context.Context, key string) error {
+	err := c.deleteFrom(ctx, key, len(c.layers))
+	c.broadcast(key)
+	return err
+}
+
+// Invalidate drops the key from every layer but the lowest one (the source of truth), and broadcasts the
+// invalidation, if configured
+func (c *MultiLevelCacheImpl) Invalidate(ctx context.Context, key string) error {
+	err := c.deleteFrom(ctx, key, len(c.layers)-1)
+	c.broadcast(key)
+	return err
+}
+
+// Close stops listening for invalidations from other instances
+func (c *MultiLevelCacheImpl) Close() {
+	if c.unsubscribe != nil {
+		c.unsubscribe()
 	}
+}
 
-	if item == nil || err != nil {
-		return nil, &Miss{Where: "ALL_LEVELS", Key: key}
+// setLayer writes to a layer using its configured TTL, if any
+func (c *MultiLevelCacheImpl) setLayer(ctx context.Context, index int, key string, value interface{}) error {
+	if index < len(c.layerTTLs) && c.layerTTLs[index] > 0 {
+		if layer, ok := c.layers[index].(MLCLayerWithTTL); ok {
+			return layer.SetWithTTL(ctx, key, value, c.layerTTLs[index])
+		}
 	}
+	return c.layers[index].Set(ctx, key, value)
+}
 
-	// Update upper layers if any
-	for _, index := range toUpdate {
-		if index < len(c.layers) { // Ignore any awkward index (if any)
-			err := c.layers[index].Set(ctx, key, item)
-			if err != nil {
+// cacheMiss stores a negative entry in the upper layers that missed the key. The lowest layer is never updated
+func (c *MultiLevelCacheImpl) cacheMiss(ctx context.Context, key string, missed []int) {
+	for _, index := range missed {
+		if index == len(c.layers)-1 {
+			continue
+		}
+		if layer, ok := c.layers[index].(MLCLayerWithTTL); ok {
+			if err := layer.SetWithTTL(ctx, key, NegativeEntry{}, c.negativeTTL); err != nil {
 				c.logger.Error(err)
 			}
 		}
 	}
-	return item, nil
+}
+
+// deleteFrom removes the key from the first `count` layers, starting with the lowest. All layers are attempted,
+// and the first failure is returned
+func (c *MultiLevelCacheImpl) deleteFrom(ctx context.Context, key string, count int) error {
+	var first error
+	for index := count - 1; index >= 0; index-- {
+		if err := c.layers[index].Delete(ctx, key); err != nil {
+			c.logger.Error(err)
+			c.stats.layerError(index)
+			if first == nil {
+				first = fmt.Errorf("error deleting key %s from layer %d: %w", key, index, err)
+			}
+		}
+	}
+	return first
+}
+
+func (c *MultiLevelCacheImpl) broadcast(key string) {
+	if c.bus == nil {
+		return
+	}
+	if err := c.bus.Publish(InvalidationMessage{Origin: c.instanceID, Key: key}); err != nil {
+		c.logger.Error(fmt.Sprintf("error broadcasting invalidation for key %s: %s", key, err.Error()))
+	}
+}
+
+func (c *MultiLevelCacheImpl) handleInvalidation(message InvalidationMessage) {
+	if message.Origin == c.instanceID {
+		return
+	}
+	c.deleteFrom(context.Background(), message.Key, c.localLayers)
 }
 
 // NewMultiLevel creates and returns a new MultiLevelCache instance
 func NewMultiLevel(layers []MLCLayer, logger logging.LoggerInterface) (*MultiLevelCacheImpl, error) {
+	return NewMultiLevelWithOptions(layers, nil, logger)
+}
+
+// NewMultiLevelWithOptions creates and returns a new MultiLevelCache instance with a custom setup
+func NewMultiLevelWithOptions(layers []MLCLayer, options *MultiLevelCacheOptions, logger logging.LoggerInterface) (*MultiLevelCacheImpl, error) {
 	if logger == nil {
 		logger = logging.NewLogger(nil)
 	}
+	if options == nil {
+		options = &MultiLevelCacheOptions{}
+	}
+
+	localLayers := options.LocalLayers
+	if localLayers <= 0 {
+		localLayers = 1
+	}
+	if len(options.LayerTTLs) > len(layers) {
+		return nil, fmt.Errorf("got %d layer TTLs for %d layers", len(options.LayerTTLs), len(layers))
+	}
+	if options.InvalidationBus != nil && localLayers > len(layers) {
+		return nil, fmt.Errorf("local layers (%d) cannot exceed the number of layers (%d)", localLayers, len(layers))
+	}
+
+	c := &MultiLevelCacheImpl{
+		layers:      layers,
+		writeMode:   options.WriteMode,
+		localLayers: localLayers,
+		negativeTTL: options.NegativeTTL,
+		layerTTLs:   options.LayerTTLs,
+		stats:       newMultiLevelStats(len(layers)),
+		logger:      logger,
+	}
+	if options.InvalidationBus != nil {
+		id := make([]byte, 8)
+		if _, err := rand.Read(id); err != nil {
+			return nil, fmt.Errorf("error generating instance id: %w", err)
+		}
+		c.instanceID = hex.EncodeToString(id)
+		c.bus = options.InvalidationBus
+		c.unsubscribe = c.bus.Subscribe(c.handleInvalidation)
+	}
 
-	return &MultiLevelCacheImpl{layers: layers, logger: logger}, nil
+	return c, nil
 }
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/splitio/go-toolkit/v5/logging"
 )
@@ -12,6 +14,7 @@ import (
 type LayerMock struct {
 	getCall func(ctx context.Context, key string) (interface{}, error)
 	setCall func(ctx context.Context, key string, value interface{}) error
+	delCall func(ctx context.Context, key string) error
 }
 
 func (m *LayerMock) Get(ctx context.Context, key string) (interface{}, error) {
@@ -22,6 +25,62 @@ func (m *LayerMock) Set(ctx context.Context, key string, value interface{}) erro
 	return m.setCall(ctx, key, value)
 }
 
+func (m *LayerMock) Delete(ctx context.Context, key string) error {
+	return m.delCall(ctx, key)
+}
+
+// mapLayer is a simple in-memory layer used to check the state of every level after an operation
+type mapLayer struct {
+	items map[string]interface{}
+	ttls  map[string]time.Duration
+	mutex sync.Mutex
+}
+
+func newMapLayer(items map[string]interface{}) *mapLayer {
+	if items == nil {
+		items = make(map[string]interface{})
+	}
+	return &mapLayer{items: items, ttls: make(map[string]time.Duration)}
+}
+
+func (m *mapLayer) Get(ctx context.Context, key string) (interface{}, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if value, ok := m.items[key]; ok {
+		return value, nil
+	}
+	return nil, &Miss{Where: "MAP", Key: key}
+}
+
+func (m *mapLayer) Set(ctx context.Context, key string, value interface{}) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.items[key] = value
+	return nil
+}
+
+func (m *mapLayer) SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.items[key] = value
+	m.ttls[key] = ttl
+	return nil
+}
+
+func (m *mapLayer) Delete(ctx context.Context, key string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	delete(m.items, key)
+	return nil
+}
+
+func (m *mapLayer) has(key string) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	_, ok := m.items[key]
+	return ok
+}
+
 type callTracker struct {
 	calls map[string]int
 	t     *testing.T
@@ -211,3 +270,199 @@ func TestMultiLevelCache(t *testing.T) {
 	calls.checkCall("top:get:key4", 1)
 	calls.checkTotalCalls(3)
 }
+
+func TestMultiLevelCacheWriteThrough(t *testing.T) {
+	top, bottom := newMapLayer(nil), newMapLayer(nil)
+	cacheML, _ := NewMultiLevel([]MLCLayer{top, bottom}, nil)
+
+	if err := cacheML.Set(context.TODO(), "key1", "value1"); err != nil {
+		t.Error("No error should have been returned. Got: ", err)
+	}
+	if !top.has("key1") || !bottom.has("key1") {
+		t.Error("value should have been written to every layer")
+	}
+
+	failing := &LayerMock{
+		setCall: func(ctx context.Context, key string, value interface{}) error { return errors.New("someError") },
+	}
+	cacheML, _ = NewMultiLevel([]MLCLayer{top, failing}, nil)
+	if err := cacheML.Set(context.TODO(), "key2", "value2"); err == nil {
+		t.Error("failing to write to the lowest layer should be reported")
+	}
+	if top.has("key2") {
+		t.Error("upper layers should not be written if the lowest one fails")
+	}
+}
+
+func TestMultiLevelCacheWriteAround(t *testing.T) {
+	top, bottom := newMapLayer(map[string]interface{}{"key1": "old"}), newMapLayer(nil)
+	cacheML, _ := NewMultiLevelWithOptions([]MLCLayer{top, bottom}, &MultiLevelCacheOptions{WriteMode: WriteAround}, nil)
+
+	if err := cacheML.Set(context.TODO(), "key1", "value1"); err != nil {
+		t.Error("No error should have been returned. Got: ", err)
+	}
+	if top.has("key1") || !bottom.has("key1") {
+		t.Error("value should have been written only to the lowest layer, and dropped from upper ones")
+	}
+
+	if value, _ := cacheML.Get(context.TODO(), "key1"); value != "value1" || !top.has("key1") {
+		t.Error("upper layers should be backfilled on read. Got: ", value)
+	}
+}
+
+func TestMultiLevelCacheDeleteAndInvalidate(t *testing.T) {
+	items := func() map[string]interface{} { return map[string]interface{}{"key1": 1, "key2": 2} }
+	top, mid, bottom := newMapLayer(items()), newMapLayer(items()), newMapLayer(items())
+	cacheML, _ := NewMultiLevel([]MLCLayer{top, mid, bottom}, nil)
+
+	if err := cacheML.Delete(context.TODO(), "key1"); err != nil {
+		t.Error("No error should have been returned. Got: ", err)
+	}
+	if top.has("key1") || mid.has("key1") || bottom.has("key1") {
+		t.Error("key should have been removed from every layer")
+	}
+
+	if err := cacheML.Invalidate(context.TODO(), "key2"); err != nil {
+		t.Error("No error should have been returned. Got: ", err)
+	}
+	if top.has("key2") || mid.has("key2") || !bottom.has("key2") {
+		t.Error("key should have been removed from every layer but the lowest")
+	}
+
+	calls := newCallTracker(t)
+	failing := &LayerMock{
+		delCall: func(ctx context.Context, key string) error {
+			calls.track("failing:del")
+			return errors.New("someError")
+		},
+	}
+	cacheML, _ = NewMultiLevel([]MLCLayer{top, failing, bottom}, nil)
+	bottom.Set(context.TODO(), "key3", 3)
+	top.Set(context.TODO(), "key3", 3)
+	if err := cacheML.Delete(context.TODO(), "key3"); err == nil {
+		t.Error("layer errors should be reported")
+	}
+	calls.checkCall("failing:del", 1)
+	if top.has("key3") || bottom.has("key3") {
+		t.Error("remaining layers should be cleared despite the failure")
+	}
+}
+
+func TestMultiLevelCacheInvalidationBroadcast(t *testing.T) {
+	bus := NewLocalInvalidationBus()
+	shared := newMapLayer(nil)
+	local1, local2 := newMapLayer(nil), newMapLayer(nil)
+	options := &MultiLevelCacheOptions{InvalidationBus: bus}
+	cache1, _ := NewMultiLevelWithOptions([]MLCLayer{local1, shared}, options, nil)
+	cache2, _ := NewMultiLevelWithOptions([]MLCLayer{local2, shared}, options, nil)
+
+	cache1.Set(context.TODO(), "key1", "value1")
+	cache2.Get(context.TODO(), "key1")
+	if !local1.has("key1") || !local2.has("key1") {
+		t.Error("both instances should have the key in their local layer")
+	}
+
+	cache1.Set(context.TODO(), "key1", "value2")
+	if local2.has("key1") {
+		t.Error("writes should invalidate the other instance's local copy")
+	}
+	if value, _ := cache2.Get(context.TODO(), "key1"); value != "value2" {
+		t.Error("the other instance should read the new value. Got: ", value)
+	}
+
+	cache1.Invalidate(context.TODO(), "key1")
+	if local1.has("key1") || local2.has("key1") {
+		t.Error("invalidation should have been propagated to the other instance")
+	}
+
+	cache2.Close()
+	local2.Set(context.TODO(), "key1", "stale")
+	cache1.Invalidate(context.TODO(), "key1")
+	if !local2.has("key1") {
+		t.Error("closed instances should no longer receive invalidations")
+	}
+
+	if _, err := NewMultiLevelWithOptions([]MLCLayer{local1}, &MultiLevelCacheOptions{InvalidationBus: bus, LocalLayers: 2}, nil); err == nil {
+		t.Error("local layers cannot exceed the number of layers")
+	}
+}
+
+func TestMultiLevelCacheGetWithResult(t *testing.T) {
+	top, mid, bottom := newMapLayer(nil), newMapLayer(map[string]interface{}{"key1": 1}), newMapLayer(map[string]interface{}{"key2": 2})
+	cacheML, _ := NewMultiLevel([]MLCLayer{top, mid, bottom}, nil)
+
+	result, err := cacheML.GetWithResult(context.TODO(), "key1")
+	if err != nil || result.Value != 1 || result.Layer != 1 {
+		t.Errorf("key1 should have been served by the mid layer. Got: %+v, %v", result, err)
+	}
+	result, _ = cacheML.GetWithResult(context.TODO(), "key1")
+	if result.Layer != 0 {
+		t.Error("key1 should have been backfilled into the top layer. Served by: ", result.Layer)
+	}
+	result, _ = cacheML.GetWithResult(context.TODO(), "key2")
+	if result.Value != 2 || result.Layer != 2 {
+		t.Errorf("key2 should have been served by the bottom layer. Got: %+v", result)
+	}
+}
+
+func TestMultiLevelCacheLayerTTLs(t *testing.T) {
+	var setCalls int
+	noTTL := &LayerMock{
+		getCall: func(ctx context.Context, key string) (interface{}, error) { return nil, &Miss{Where: "MOCK", Key: key} },
+		setCall: func(ctx context.Context, key string, value interface{}) error { setCalls++; return nil },
+	}
+	top, bottom := newMapLayer(nil), newMapLayer(map[string]interface{}{"key1": 1})
+	if _, err := NewMultiLevelWithOptions([]MLCLayer{top}, &MultiLevelCacheOptions{LayerTTLs: []time.Duration{1, 2}}, nil); err == nil {
+		t.Error("more TTLs than layers should be rejected")
+	}
+
+	options := &MultiLevelCacheOptions{LayerTTLs: []time.Duration{time.Second, time.Minute}}
+	cacheML, _ := NewMultiLevelWithOptions([]MLCLayer{top, noTTL, bottom}, options, nil)
+	cacheML.Get(context.TODO(), "key1")
+	if top.ttls["key1"] != time.Second || setCalls != 1 {
+		t.Error("backfills should use the layer ttl when supported. Got: ", top.ttls["key1"], setCalls)
+	}
+
+	cacheML.Set(context.TODO(), "key2", 2)
+	if top.ttls["key2"] != time.Second || setCalls != 2 {
+		t.Error("write-throughs should use the layer ttl when supported. Got: ", top.ttls["key2"], setCalls)
+	}
+	if _, ok := bottom.ttls["key2"]; ok {
+		t.Error("layers without a configured ttl should use their default")
+	}
+}
+
+func TestMultiLevelCacheNegativeCaching(t *testing.T) {
+	calls := newCallTracker(t)
+	noTTL := &LayerMock{
+		getCall: func(ctx context.Context, key string) (interface{}, error) { return nil, &Miss{Where: "MOCK", Key: key} },
+		setCall: func(ctx context.Context, key string, value interface{}) error {
+			calls.track("noTTL:set")
+			return nil
+		},
+	}
+	top, bottom := newMapLayer(nil), newMapLayer(nil)
+	cacheML, _ := NewMultiLevelWithOptions([]MLCLayer{top, noTTL, bottom}, &MultiLevelCacheOptions{NegativeTTL: time.Second}, nil)
+
+	_, err := cacheML.Get(context.TODO(), "key1")
+	if asMiss, ok := err.(*Miss); !ok || asMiss.Where != "ALL_LEVELS" {
+		t.Error("a miss should be returned. Got: ", err)
+	}
+	if top.items["key1"] != (NegativeEntry{}) || top.ttls["key1"] != time.Second {
+		t.Error("the miss should have been cached in the top layer. Got: ", top.items["key1"], top.ttls["key1"])
+	}
+	if bottom.has("key1") {
+		t.Error("the lowest layer should never hold negative entries")
+	}
+	calls.checkTotalCalls(0)
+
+	bottom.Set(context.TODO(), "key1", 1)
+	if _, err := cacheML.Get(context.TODO(), "key1"); err == nil {
+		t.Error("the negative entry should be served until it expires or the key is written")
+	}
+
+	cacheML.Set(context.TODO(), "key1", 1)
+	if value, err := cacheML.Get(context.TODO(), "key1"); err != nil || value != 1 {
+		t.Error("writes should override negative entries. Got: ", value, err)
+	}
+}
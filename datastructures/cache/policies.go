@@ -0,0 +1,427 @@
+package cache
+
+import (
+	"container/list"
+
+	"github.com/splitio/go-toolkit/v5/hasher"
+)
+
+// EvictionReason indicates why an entry was removed from a cache
+type EvictionReason int
+
+// Eviction reasons
+const (
+	EvictionCapacity EvictionReason = iota
+	EvictionExpired
+	EvictionDeleted
+)
+
+// EvictionPolicy decides which entry to drop when a cache is full.
+// Implementations are always called with the cache lock held, and therefore need not be thread-safe
+type EvictionPolicy interface {
+	// Added is called when a new key is inserted
+	Added(key string)
+	// Accessed is called when an existing key is read or updated
+	Accessed(key string)
+	// Removed is called when a key leaves the cache, for any reason
+	Removed(key string, reason EvictionReason)
+	// Victim returns the key to evict in order to make room. candidate is the key about to be inserted, if any
+	Victim(candidate string) string
+}
+
+// FIFOPolicy evicts entries in insertion order, regardless of how they're accessed
+type FIFOPolicy struct {
+	order *list.List
+	nodes map[string]*list.Element
+}
+
+// NewFIFOPolicy constructs a new FIFO eviction policy
+func NewFIFOPolicy() *FIFOPolicy {
+	return &FIFOPolicy{order: new(list.List), nodes: make(map[string]*list.Element)}
+}
+
+// Added registers the key as the newest one
+func (p *FIFOPolicy) Added(key string) {
+	p.nodes[key] = p.order.PushBack(key)
+}
+
+// Accessed does nothing, since accesses don't affect the eviction order
+func (p *FIFOPolicy) Accessed(key string) {}
+
+// Removed forgets the key
+func (p *FIFOPolicy) Removed(key string, reason EvictionReason) {
+	if node, ok := p.nodes[key]; ok {
+		p.order.Remove(node)
+		delete(p.nodes, key)
+	}
+}
+
+// Victim returns the oldest key
+func (p *FIFOPolicy) Victim(candidate string) string {
+	if front := p.order.Front(); front != nil {
+		return front.Value.(string)
+	}
+	return ""
+}
+
+// LFUPolicy evicts the least frequently used entry, breaking ties by recency
+type LFUPolicy struct {
+	buckets map[int]*list.List // frequency -> keys, most recently used first
+	nodes   map[string]*list.Element
+	freqs   map[string]int
+	minFreq int
+}
+
+// NewLFUPolicy constructs a new LFU eviction policy
+func NewLFUPolicy() *LFUPolicy {
+	return &LFUPolicy{
+		buckets: make(map[int]*list.List),
+		nodes:   make(map[string]*list.Element),
+		freqs:   make(map[string]int),
+	}
+}
+
+// Added registers the key with a frequency of 1
+func (p *LFUPolicy) Added(key string) {
+	p.push(key, 1)
+	p.minFreq = 1
+}
+
+// Accessed increments the key's frequency
+func (p *LFUPolicy) Accessed(key string) {
+	freq, ok := p.freqs[key]
+	if !ok {
+		return
+	}
+	p.unlink(key, freq)
+	if freq == p.minFreq && p.buckets[freq] == nil {
+		p.minFreq++
+	}
+	p.push(key, freq+1)
+}
+
+// Removed forgets the key
+func (p *LFUPolicy) Removed(key string, reason EvictionReason) {
+	if freq, ok := p.freqs[key]; ok {
+		p.unlink(key, freq)
+		delete(p.freqs, key)
+	}
+}
+
+// Victim returns the least recently used key among the least frequently used ones
+func (p *LFUPolicy) Victim(candidate string) string {
+	if len(p.freqs) == 0 {
+		return ""
+	}
+	for p.buckets[p.minFreq] == nil { // minFreq might be stale after removals
+		p.minFreq++
+	}
+	return p.buckets[p.minFreq].Back().Value.(string)
+}
+
+func (p *LFUPolicy) push(key string, freq int) {
+	bucket, ok := p.buckets[freq]
+	if !ok {
+		bucket = new(list.List)
+		p.buckets[freq] = bucket
+	}
+	p.nodes[key] = bucket.PushFront(key)
+	p.freqs[key] = freq
+}
+
+func (p *LFUPolicy) unlink(key string, freq int) {
+	bucket := p.buckets[freq]
+	bucket.Remove(p.nodes[key])
+	delete(p.nodes, key)
+	if bucket.Len() == 0 {
+		delete(p.buckets, freq)
+	}
+}
+
+// ARCPolicy implements the Adaptive Replacement Cache algorithm, which balances recency & frequency
+// by tracking recently evicted keys (ghosts) and adapting the target size of each half accordingly
+type ARCPolicy struct {
+	capacity int
+	target   int // desired size of t1
+	t1       *list.List
+	t2       *list.List
+	b1       *list.List
+	b2       *list.List
+	nodes    map[string]*list.Element
+	lists    map[string]*list.List
+}
+
+// NewARCPolicy constructs a new ARC eviction policy. capacity should match the cache's maximum size
+func NewARCPolicy(capacity int) *ARCPolicy {
+	return &ARCPolicy{
+		capacity: capacity,
+		t1:       new(list.List),
+		t2:       new(list.List),
+		b1:       new(list.List),
+		b2:       new(list.List),
+		nodes:    make(map[string]*list.Element),
+		lists:    make(map[string]*list.List),
+	}
+}
+
+// Added inserts the key in t1, or in t2 if it was recently evicted, adapting the target size on ghost hits
+func (p *ARCPolicy) Added(key string) {
+	switch p.lists[key] {
+	case p.b1:
+		p.target = minInt(p.capacity, p.target+maxInt(p.b2.Len()/maxInt(p.b1.Len(), 1), 1))
+		p.unlink(key)
+		p.push(key, p.t2)
+	case p.b2:
+		p.target = maxInt(0, p.target-maxInt(p.b1.Len()/maxInt(p.b2.Len(), 1), 1))
+		p.unlink(key)
+		p.push(key, p.t2)
+	default:
+		p.push(key, p.t1)
+	}
+	p.trimGhosts()
+}
+
+// Accessed promotes the key to the front of t2
+func (p *ARCPolicy) Accessed(key string) {
+	switch p.lists[key] {
+	case p.t1, p.t2:
+		p.unlink(key)
+		p.push(key, p.t2)
+	}
+}
+
+// Removed moves evicted keys to the ghost lists and forgets keys removed for any other reason
+func (p *ARCPolicy) Removed(key string, reason EvictionReason) {
+	from := p.lists[key]
+	if from != p.t1 && from != p.t2 {
+		return
+	}
+
+	p.unlink(key)
+	if reason != EvictionCapacity {
+		return
+	}
+
+	if from == p.t1 {
+		p.push(key, p.b1)
+	} else {
+		p.push(key, p.b2)
+	}
+	p.trimGhosts()
+}
+
+// Victim returns the LRU key of either t1 or t2, depending on the current target size
+func (p *ARCPolicy) Victim(candidate string) string {
+	t1Len := p.t1.Len()
+	if t1Len > 0 && (t1Len > p.target || (p.lists[candidate] == p.b2 && t1Len == p.target) || p.t2.Len() == 0) {
+		return p.t1.Back().Value.(string)
+	}
+	if back := p.t2.Back(); back != nil {
+		return back.Value.(string)
+	}
+	return ""
+}
+
+func (p *ARCPolicy) push(key string, to *list.List) {
+	p.nodes[key] = to.PushFront(key)
+	p.lists[key] = to
+}
+
+func (p *ARCPolicy) unlink(key string) {
+	if from, ok := p.lists[key]; ok {
+		from.Remove(p.nodes[key])
+		delete(p.nodes, key)
+		delete(p.lists, key)
+	}
+}
+
+func (p *ARCPolicy) trimGhosts() {
+	for p.b1.Len() > 0 && p.t1.Len()+p.b1.Len() > p.capacity {
+		p.unlink(p.b1.Back().Value.(string))
+	}
+	for p.b2.Len() > 0 && p.t1.Len()+p.t2.Len()+p.b1.Len()+p.b2.Len() > 2*p.capacity {
+		p.unlink(p.b2.Back().Value.(string))
+	}
+}
+
+// TinyLFUPolicy implements W-TinyLFU: new keys enter a small LRU window, and keys leaving it are only admitted
+// into the main segmented LRU if they're estimated to be more frequently used than the main victim.
+// Frequencies are tracked with an aging count-min sketch
+type TinyLFUPolicy struct {
+	window       *list.List
+	probation    *list.List
+	protected    *list.List
+	windowCap    int
+	protectedCap int
+	nodes        map[string]*list.Element
+	lists        map[string]*list.List
+	sketch       *countMinSketch
+}
+
+// NewTinyLFUPolicy constructs a new W-TinyLFU eviction policy. capacity should match the cache's maximum size
+func NewTinyLFUPolicy(capacity int) *TinyLFUPolicy {
+	windowCap := maxInt(1, capacity/100)
+	return &TinyLFUPolicy{
+		window:       new(list.List),
+		probation:    new(list.List),
+		protected:    new(list.List),
+		windowCap:    windowCap,
+		protectedCap: maxInt(1, (capacity-windowCap)*8/10),
+		nodes:        make(map[string]*list.Element),
+		lists:        make(map[string]*list.List),
+		sketch:       newCountMinSketch(capacity),
+	}
+}
+
+// Added inserts the key in the window. While the cache is not full, keys overflowing the window move to the
+// main space without any admission contest
+func (p *TinyLFUPolicy) Added(key string) {
+	p.sketch.increment(key)
+	p.push(key, p.window)
+	for p.window.Len() > p.windowCap {
+		overflow := p.window.Back().Value.(string)
+		p.unlink(overflow)
+		p.push(overflow, p.probation)
+	}
+}
+
+// Accessed records the access and promotes the key within its segment
+func (p *TinyLFUPolicy) Accessed(key string) {
+	p.sketch.increment(key)
+	switch p.lists[key] {
+	case p.window:
+		p.unlink(key)
+		p.push(key, p.window)
+	case p.probation, p.protected:
+		p.unlink(key)
+		p.push(key, p.protected)
+		for p.protected.Len() > p.protectedCap {
+			demoted := p.protected.Back().Value.(string)
+			p.unlink(demoted)
+			p.push(demoted, p.probation)
+		}
+	}
+}
+
+// Removed forgets the key
+func (p *TinyLFUPolicy) Removed(key string, reason EvictionReason) {
+	p.unlink(key)
+}
+
+// Victim moves the window's LRU key into the main space if it wins the admission contest against the main victim,
+// and returns the loser
+func (p *TinyLFUPolicy) Victim(candidate string) string {
+	mainVictim := p.probation.Back()
+	if mainVictim == nil {
+		mainVictim = p.protected.Back()
+	}
+
+	windowVictim := p.window.Back()
+	if windowVictim == nil || (p.window.Len() < p.windowCap && mainVictim != nil) {
+		if mainVictim == nil {
+			return ""
+		}
+		// Window within bounds: make room in the main space
+		return mainVictim.Value.(string)
+	}
+
+	windowKey := windowVictim.Value.(string)
+	if mainVictim == nil {
+		return windowKey
+	}
+
+	mainKey := mainVictim.Value.(string)
+	if p.sketch.estimate(windowKey) <= p.sketch.estimate(mainKey) {
+		return windowKey
+	}
+
+	p.unlink(windowKey)
+	p.push(windowKey, p.probation)
+	return mainKey
+}
+
+func (p *TinyLFUPolicy) push(key string, to *list.List) {
+	p.nodes[key] = to.PushFront(key)
+	p.lists[key] = to
+}
+
+func (p *TinyLFUPolicy) unlink(key string) {
+	if from, ok := p.lists[key]; ok {
+		from.Remove(p.nodes[key])
+		delete(p.nodes, key)
+		delete(p.lists, key)
+	}
+}
+
+const (
+	sketchDepth      = 4
+	sketchMaxCounter = 15
+)
+
+// countMinSketch estimates key frequencies with 4-bit-like saturating counters, halving them periodically
+// so that old popularity fades away
+type countMinSketch struct {
+	counters  [sketchDepth][]uint8
+	mask      uint32
+	additions int
+	resetAt   int
+}
+
+func newCountMinSketch(capacity int) *countMinSketch {
+	width := 16
+	for width < 4*capacity { // wide enough to keep collisions between one-time keys low
+		width <<= 1
+	}
+
+	sketch := &countMinSketch{mask: uint32(width - 1), resetAt: 10 * maxInt(capacity, 1)}
+	for row := range sketch.counters {
+		sketch.counters[row] = make([]uint8, width)
+	}
+	return sketch
+}
+
+func (s *countMinSketch) increment(key string) {
+	for row := range s.counters {
+		if idx := s.index(key, row); s.counters[row][idx] < sketchMaxCounter {
+			s.counters[row][idx]++
+		}
+	}
+
+	s.additions++
+	if s.additions >= s.resetAt {
+		s.additions /= 2
+		for row := range s.counters {
+			for idx := range s.counters[row] {
+				s.counters[row][idx] /= 2
+			}
+		}
+	}
+}
+
+func (s *countMinSketch) estimate(key string) uint8 {
+	min := uint8(sketchMaxCounter)
+	for row := range s.counters {
+		if value := s.counters[row][s.index(key, row)]; value < min {
+			min = value
+		}
+	}
+	return min
+}
+
+func (s *countMinSketch) index(key string, row int) uint32 {
+	return hasher.Sum32WithSeed([]byte(key), uint32(row)) & s.mask
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,148 @@
+package cache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestFIFOPolicy(t *testing.T) {
+	cache, _ := NewLocalCacheWithOptions(&LocalCacheOptions{MaxSize: 3, TTL: time.Minute, Policy: NewFIFOPolicy()})
+	cache.Set("k1", 1)
+	cache.Set("k2", 2)
+	cache.Set("k3", 3)
+	cache.Get("k1") // accessing doesn't save k1 from eviction
+	cache.Set("k4", 4)
+
+	if _, err := cache.Get("k1"); err == nil {
+		t.Error("k1 should have been evicted")
+	}
+	for _, key := range []string{"k2", "k3", "k4"} {
+		if _, err := cache.Get(key); err != nil {
+			t.Errorf("%s should be present. Got: %s", key, err)
+		}
+	}
+}
+
+func TestLFUPolicy(t *testing.T) {
+	cache, _ := NewLocalCacheWithOptions(&LocalCacheOptions{MaxSize: 3, TTL: time.Minute, Policy: NewLFUPolicy()})
+	cache.Set("k1", 1)
+	cache.Set("k2", 2)
+	cache.Set("k3", 3)
+	cache.Get("k1")
+	cache.Get("k1")
+	cache.Get("k3")
+	cache.Set("k4", 4) // k2 is the least frequently used
+
+	if _, err := cache.Get("k2"); err == nil {
+		t.Error("k2 should have been evicted")
+	}
+
+	cache.Delete("k1")
+	cache.Set("k5", 5)
+	cache.Set("k6", 6) // k4 & k5 have the same frequency. k4 is the least recently used among them
+	if _, err := cache.Get("k4"); err == nil {
+		t.Error("k4 should have been evicted")
+	}
+	for _, key := range []string{"k3", "k5", "k6"} {
+		if _, err := cache.Get(key); err != nil {
+			t.Errorf("%s should be present. Got: %s", key, err)
+		}
+	}
+}
+
+func TestARCPolicy(t *testing.T) {
+	cache, _ := NewLocalCacheWithOptions(&LocalCacheOptions{MaxSize: 4, TTL: time.Minute, Policy: NewARCPolicy(4)})
+
+	// k1 & k2 are frequently used, and should survive a scan of one-time keys
+	cache.Set("k1", 1)
+	cache.Set("k2", 2)
+	cache.Get("k1")
+	cache.Get("k2")
+	for i := 0; i < 20; i++ {
+		cache.Set(fmt.Sprintf("scan%d", i), i)
+	}
+
+	for _, key := range []string{"k1", "k2"} {
+		if _, err := cache.Get(key); err != nil {
+			t.Errorf("%s should have survived the scan. Got: %s", key, err)
+		}
+	}
+	if cache.lru.Len() != 4 {
+		t.Error("cache should be full. Len: ", cache.lru.Len())
+	}
+
+	policy := cache.policy.(*ARCPolicy)
+	if policy.b1.Len() == 0 {
+		t.Error("evicted scan keys should be tracked as ghosts")
+	}
+	if policy.t1.Len()+policy.b1.Len() > 4 || policy.t1.Len()+policy.t2.Len()+policy.b1.Len()+policy.b2.Len() > 8 {
+		t.Error("ghost lists should be bounded")
+	}
+
+	// a ghost hit on b1 should increase the target size of t1
+	ghost := policy.b1.Front().Value.(string)
+	cache.Set(ghost, 0)
+	if policy.target == 0 {
+		t.Error("target should have been adapted after a ghost hit")
+	}
+	if policy.lists[ghost] != policy.t2 {
+		t.Error("keys found in ghost lists should be inserted in t2")
+	}
+}
+
+func TestTinyLFUPolicy(t *testing.T) {
+	cache, _ := NewLocalCacheWithOptions(&LocalCacheOptions{MaxSize: 100, TTL: time.Minute, Policy: NewTinyLFUPolicy(100)})
+
+	// hot keys are accessed several times
+	for i := 0; i < 50; i++ {
+		cache.Set(fmt.Sprintf("hot%d", i), i)
+		for j := 0; j < 5; j++ {
+			cache.Get(fmt.Sprintf("hot%d", i))
+		}
+	}
+
+	// a long scan of one-time keys should not flush the hot ones
+	for i := 0; i < 1000; i++ {
+		cache.Set(fmt.Sprintf("scan%d", i), i)
+	}
+
+	for i := 0; i < 50; i++ {
+		if _, err := cache.Get(fmt.Sprintf("hot%d", i)); err != nil {
+			t.Errorf("hot%d should have survived the scan. Got: %s", i, err)
+		}
+	}
+	if cache.lru.Len() != 100 || len(cache.items) != 100 {
+		t.Error("cache should be full. Len: ", cache.lru.Len())
+	}
+
+	policy := cache.policy.(*TinyLFUPolicy)
+	if tracked := policy.window.Len() + policy.probation.Len() + policy.protected.Len(); tracked != 100 {
+		t.Error("policy should track every key in the cache. Tracks: ", tracked)
+	}
+	if policy.window.Len() > policy.windowCap || policy.protected.Len() > policy.protectedCap {
+		t.Error("segments should be bounded")
+	}
+}
+
+func TestCountMinSketchAging(t *testing.T) {
+	sketch := newCountMinSketch(16) // counters are halved every 160 increments
+	for i := 0; i < 10; i++ {
+		sketch.increment("key")
+	}
+	if estimate := sketch.estimate("key"); estimate != 10 {
+		t.Error("estimate should be 10. Is: ", estimate)
+	}
+	for i := 0; i < 20; i++ {
+		sketch.increment("key")
+	}
+	if estimate := sketch.estimate("key"); estimate != sketchMaxCounter {
+		t.Error("estimate should be capped. Is: ", estimate)
+	}
+	for i := 0; i < 130; i++ {
+		sketch.increment(fmt.Sprintf("other%d", i))
+	}
+	if estimate := sketch.estimate("key"); estimate != sketchMaxCounter/2 {
+		t.Error("estimate should have been halved. Is: ", estimate)
+	}
+}
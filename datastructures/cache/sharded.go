@@ -0,0 +1,154 @@
+package cache
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/splitio/go-toolkit/v5/hasher"
+)
+
+const (
+	evictionSamples = 5
+
+	// accessResolution is the minimum time between updates of an entry's access timestamp. Skipping redundant
+	// writes avoids contention between cores reading the same hot entries
+	accessResolution = int64(time.Millisecond)
+)
+
+// ShardedLocalCacheImpl is an in-memory TTL cache split in independently locked shards, meant for read-heavy,
+// highly concurrent workloads. Reads only take a shared lock, which is possible because recency is tracked with
+// an access timestamp instead of a list. When a shard is full, the least recently used among a small sample
+// of its entries is evicted, which approximates LRU.
+type ShardedLocalCacheImpl struct {
+	shards []*cacheShard
+	mask   uint32
+	ttl    time.Duration
+}
+
+type cacheShard struct {
+	mutex  sync.RWMutex
+	maxLen int
+	items  map[string]*shardEntry
+}
+
+type shardEntry struct {
+	value      interface{}
+	expiration int64
+	lastAccess int64 // unix nanos, updated atomically under the read lock
+}
+
+// NewShardedLocalCache returns a new ShardedLocalCacheImpl holding up to maxSize items spread across the requested
+// number of shards, rounded up to a power of 2. The shard count is reduced if needed so that every shard can hold at
+// least one item, and the capacity is split so that shards add up to exactly maxSize
+func NewShardedLocalCache(maxSize int, ttl time.Duration, shards int) (*ShardedLocalCacheImpl, error) {
+	if maxSize <= 0 {
+		return nil, fmt.Errorf("Cache size should be > 0. Is: %d", maxSize)
+	}
+	if shards <= 0 {
+		return nil, fmt.Errorf("Shard count should be > 0. Is: %d", shards)
+	}
+
+	count := 1
+	for count < shards {
+		count <<= 1
+	}
+	for count > maxSize {
+		count >>= 1
+	}
+
+	c := &ShardedLocalCacheImpl{shards: make([]*cacheShard, count), mask: uint32(count - 1), ttl: ttl}
+	for idx := range c.shards {
+		perShard := maxSize / count
+		if idx < maxSize%count {
+			perShard++
+		}
+		c.shards[idx] = &cacheShard{maxLen: perShard, items: make(map[string]*shardEntry, perShard)}
+	}
+	return c, nil
+}
+
+// Get retrieves an item if exist, nil + an error otherwise
+func (c *ShardedLocalCacheImpl) Get(key string) (interface{}, error) {
+	shard := c.shard(key)
+	shard.mutex.RLock()
+	defer shard.mutex.RUnlock()
+
+	e, ok := shard.items[key]
+	if !ok {
+		return nil, &Miss{Where: "LOCAL", Key: key}
+	}
+
+	now := time.Now().UnixNano()
+	if now > e.expiration {
+		return nil, &Expired{Key: key, Value: e.value, When: time.Unix(0, e.expiration)}
+	}
+
+	if now-atomic.LoadInt64(&e.lastAccess) > accessResolution {
+		atomic.StoreInt64(&e.lastAccess, now)
+	}
+	return e.value, nil
+}
+
+// Set adds a new item. If the shard is full, an approximately least recently used item is evicted.
+// This method never fails.
+func (c *ShardedLocalCacheImpl) Set(key string, value interface{}) error {
+	now := time.Now().UnixNano()
+	shard := c.shard(key)
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	if _, ok := shard.items[key]; !ok && len(shard.items) >= shard.maxLen {
+		shard.evict(now)
+	}
+	shard.items[key] = &shardEntry{value: value, expiration: now + int64(c.ttl), lastAccess: now}
+	return nil
+}
+
+// Delete removes an item, returning true if it was present
+func (c *ShardedLocalCacheImpl) Delete(key string) bool {
+	shard := c.shard(key)
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+	_, ok := shard.items[key]
+	delete(shard.items, key)
+	return ok
+}
+
+// Len returns the number of items stored across all shards, including expired ones not yet evicted
+func (c *ShardedLocalCacheImpl) Len() int {
+	total := 0
+	for _, shard := range c.shards {
+		shard.mutex.RLock()
+		total += len(shard.items)
+		shard.mutex.RUnlock()
+	}
+	return total
+}
+
+func (c *ShardedLocalCacheImpl) shard(key string) *cacheShard {
+	return c.shards[hasher.Sum32WithSeed([]byte(key), 0)&c.mask]
+}
+
+// evict removes an expired entry or the least recently used one among a sample. Must be called with the lock held
+func (s *cacheShard) evict(now int64) {
+	var victim string
+	oldest := int64(-1)
+	sampled := 0
+	for key, e := range s.items { // map iteration order is randomized, which gives us the sample
+		if now > e.expiration {
+			victim = key
+			break
+		}
+		if lastAccess := atomic.LoadInt64(&e.lastAccess); oldest == -1 || lastAccess < oldest {
+			victim, oldest = key, lastAccess
+		}
+		if sampled++; sampled >= evictionSamples {
+			break
+		}
+	}
+	delete(s.items, victim)
+}
+
+var _ LocalCache = (*ShardedLocalCacheImpl)(nil)
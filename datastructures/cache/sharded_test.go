@@ -0,0 +1,154 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestShardedLocalCache(t *testing.T) {
+	if _, err := NewShardedLocalCache(0, time.Second, 4); err == nil {
+		t.Error("a zero size should be rejected")
+	}
+	if _, err := NewShardedLocalCache(10, time.Second, 0); err == nil {
+		t.Error("a zero shard count should be rejected")
+	}
+
+	cache, err := NewShardedLocalCache(100, 50*time.Millisecond, 3)
+	if err != nil {
+		t.Error("No error should have been returned. Got: ", err)
+	}
+	if len(cache.shards) != 4 || cache.shards[0].maxLen != 25 {
+		t.Error("shard count should be rounded up to a power of 2. Got: ", len(cache.shards))
+	}
+
+	for i := 0; i < 100; i++ {
+		cache.Set(fmt.Sprintf("someKey%d", i), i)
+	}
+	for i := 0; i < 100; i++ {
+		if val, err := cache.Get(fmt.Sprintf("someKey%d", i)); err == nil && val != i {
+			t.Errorf("Value for key 'someKey%d' should be %d. Is %d", i, i, val)
+		}
+	}
+	if cache.Len() > 100 {
+		t.Error("cache should be bounded. Len: ", cache.Len())
+	}
+
+	_, err = cache.Get("missing")
+	var asMiss *Miss
+	if !errors.As(err, &asMiss) || asMiss.Key != "missing" || asMiss.Where != "LOCAL" {
+		t.Errorf("Error should be a Miss. Is %+v", err)
+	}
+
+	cache.Set("toDelete", 1)
+	if !cache.Delete("toDelete") || cache.Delete("toDelete") {
+		t.Error("key should have been deleted once")
+	}
+
+	cache.Set("toExpire", "value")
+	time.Sleep(100 * time.Millisecond)
+	_, err = cache.Get("toExpire")
+	var asExpired *Expired
+	if !errors.As(err, &asExpired) || asExpired.Value != "value" || asExpired.Key != "toExpire" {
+		t.Errorf("Error should be Expired. Is %+v", err)
+	}
+}
+
+func TestShardedLocalCacheCapacity(t *testing.T) {
+	for _, tc := range []struct{ maxSize, shards, expectedShards int }{
+		{10, 64, 8},
+		{1000, 64, 64},
+		{100, 3, 4},
+		{1, 16, 1},
+	} {
+		cache, _ := NewShardedLocalCache(tc.maxSize, time.Minute, tc.shards)
+		capacity := 0
+		for _, shard := range cache.shards {
+			if shard.maxLen == 0 {
+				t.Errorf("every shard should hold at least one item. Case: %+v", tc)
+			}
+			capacity += shard.maxLen
+		}
+		if capacity != tc.maxSize || len(cache.shards) != tc.expectedShards {
+			t.Errorf("unexpected capacity (%d) or shard count (%d). Case: %+v", capacity, len(cache.shards), tc)
+		}
+
+		for i := 0; i < tc.maxSize*10; i++ {
+			cache.Set(strconv.Itoa(i), i)
+		}
+		if cache.Len() > tc.maxSize {
+			t.Errorf("cache should never hold more than %d items. Len: %d", tc.maxSize, cache.Len())
+		}
+	}
+}
+
+func TestShardedLocalCacheApproximateLRU(t *testing.T) {
+	cache, _ := NewShardedLocalCache(10, time.Minute, 1)
+	for i := 0; i < 10; i++ {
+		cache.Set(strconv.Itoa(i), i)
+	}
+
+	// Keep key 0 hot while inserting new keys
+	for i := 10; i < 50; i++ {
+		time.Sleep(2 * time.Millisecond)
+		cache.Get("0")
+		cache.Set(strconv.Itoa(i), i)
+	}
+
+	if _, err := cache.Get("0"); err != nil {
+		t.Error("the most recently used key should never be evicted. Got: ", err)
+	}
+	if cache.Len() != 10 {
+		t.Error("cache should be full. Len: ", cache.Len())
+	}
+}
+
+func TestShardedLocalCacheConcurrency(t *testing.T) {
+	cache, _ := NewShardedLocalCache(500, time.Second, 16)
+	wg := sync.WaitGroup{}
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(offset int) {
+			defer wg.Done()
+			for j := 0; j < 10000; j++ {
+				key := strconv.Itoa((offset * j) % 1000)
+				if j%4 == 0 {
+					cache.Set(key, j)
+				} else {
+					cache.Get(key)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func benchmarkParallelGets(b *testing.B, cache LocalCache) {
+	keys := make([]string, 1000)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+		cache.Set(keys[i], i)
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			cache.Get(keys[i%len(keys)])
+			i++
+		}
+	})
+}
+
+func BenchmarkLocalCacheParallelGet(b *testing.B) {
+	cache, _ := NewLocalCache(1000, time.Hour)
+	benchmarkParallelGets(b, cache)
+}
+
+func BenchmarkShardedLocalCacheParallelGet(b *testing.B) {
+	cache, _ := NewShardedLocalCache(1000, time.Hour, 64)
+	benchmarkParallelGets(b, cache)
+}
@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+)
+
+// SnapshotEntry is the persisted form of a cache entry
+type SnapshotEntry struct {
+	Key   string        `json:"key"`
+	Value interface{}   `json:"value"`
+	TTL   time.Duration `json:"ttl"` // remaining time to live when the snapshot was taken
+}
+
+// SnapshotCodec encodes & decodes cache snapshots
+type SnapshotCodec interface {
+	Encode(w io.Writer, entries []SnapshotEntry) error
+	Decode(r io.Reader) ([]SnapshotEntry, error)
+}
+
+// JSONSnapshotCodec stores snapshots as JSON, decoding values into T
+type JSONSnapshotCodec[T any] struct{}
+
+type jsonSnapshotEntry[T any] struct {
+	Key   string        `json:"key"`
+	Value T             `json:"value"`
+	TTL   time.Duration `json:"ttl"`
+}
+
+// Encode writes the entries as a JSON array
+func (JSONSnapshotCodec[T]) Encode(w io.Writer, entries []SnapshotEntry) error {
+	return json.NewEncoder(w).Encode(entries)
+}
+
+// Decode reads a JSON array of entries
+func (JSONSnapshotCodec[T]) Decode(r io.Reader) ([]SnapshotEntry, error) {
+	var decoded []jsonSnapshotEntry[T]
+	if err := json.NewDecoder(r).Decode(&decoded); err != nil {
+		return nil, err
+	}
+
+	entries := make([]SnapshotEntry, 0, len(decoded))
+	for _, e := range decoded {
+		entries = append(entries, SnapshotEntry{Key: e.Key, Value: e.Value, TTL: e.TTL})
+	}
+	return entries, nil
+}
+
+// Dump writes every non-expired entry, along with its remaining TTL, from the most to the least recently used.
+// When a Policy is set, accesses are tracked by the policy instead, so entries are written from the most to the
+// least recently inserted
+func (c *LocalCacheImpl) Dump(w io.Writer, codec SnapshotCodec) error {
+	if codec == nil {
+		return errors.New("a codec is required")
+	}
+
+	c.mutex.Lock()
+	now := time.Now()
+	entries := make([]SnapshotEntry, 0, c.lru.Len())
+	for node := c.lru.Front(); node != nil; node = node.Next() {
+		e, ok := node.Value.(entry)
+		if !ok {
+			continue
+		}
+		if remaining := c.ttls[e.key].Sub(now); remaining > 0 {
+			entries = append(entries, SnapshotEntry{Key: e.key, Value: e.value, TTL: remaining})
+		}
+	}
+	c.mutex.Unlock()
+
+	return codec.Encode(w, entries)
+}
+
+// Restore loads a snapshot written by Dump, preserving the entries' recency order & remaining TTLs.
+// Entries that don't fit are dropped starting with the least recently used ones. Returns the number
+// of entries from the snapshot present in the cache once it has been loaded
+func (c *LocalCacheImpl) Restore(r io.Reader, codec SnapshotCodec) (int, error) {
+	if codec == nil {
+		return 0, errors.New("a codec is required")
+	}
+
+	entries, err := codec.Decode(r)
+	if err != nil {
+		return 0, err
+	}
+
+	loaded := make(map[string]struct{}, len(entries))
+	for idx := len(entries) - 1; idx >= 0; idx-- {
+		if entries[idx].TTL <= 0 {
+			continue
+		}
+		if err := c.SetWithTTL(entries[idx].Key, entries[idx].Value, entries[idx].TTL); err != nil {
+			continue // the entry alone exceeds the size limit
+		}
+		loaded[entries[idx].Key] = struct{}{}
+	}
+
+	// later inserts may have evicted earlier ones if the snapshot doesn't fit
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	restored := 0
+	for key := range loaded {
+		if _, ok := c.items[key]; ok {
+			restored++
+		}
+	}
+	return restored, nil
+}
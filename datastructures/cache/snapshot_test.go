@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+type snapshotValue struct {
+	Name string `json:"name"`
+}
+
+func TestLocalCacheSnapshot(t *testing.T) {
+	cache, _ := NewLocalCache(10, time.Minute)
+	cache.Set("key1", snapshotValue{Name: "a"})
+	cache.Set("key2", snapshotValue{Name: "b"})
+	cache.SetWithTTL("key3", snapshotValue{Name: "c"}, 10*time.Millisecond)
+	cache.Set("key4", snapshotValue{Name: "d"})
+	cache.Get("key1") // LRU order: key1, key4, key3, key2
+	time.Sleep(20 * time.Millisecond)
+
+	var buffer bytes.Buffer
+	if err := cache.Dump(&buffer, JSONSnapshotCodec[snapshotValue]{}); err != nil {
+		t.Error("No error should have been returned. Got: ", err)
+	}
+
+	restored, _ := NewLocalCache(2, time.Minute)
+	count, err := restored.Restore(bytes.NewReader(buffer.Bytes()), JSONSnapshotCodec[snapshotValue]{})
+	if err != nil || count != 2 {
+		t.Error("only 2 of the 3 non-expired entries fit & should be reported as restored. Got: ", count, err)
+	}
+
+	// only the 2 most recently used entries fit
+	if val, err := restored.Get("key1"); err != nil || val != (snapshotValue{Name: "a"}) {
+		t.Error("key1 should have been restored. Got: ", val, err)
+	}
+	if val, err := restored.Get("key4"); err != nil || val != (snapshotValue{Name: "d"}) {
+		t.Error("key4 should have been restored. Got: ", val, err)
+	}
+	if _, err := restored.Get("key2"); err == nil {
+		t.Error("key2 should have been dropped as the least recently used entry")
+	}
+	if _, err := restored.Get("key3"); err == nil {
+		t.Error("expired entries should not be dumped")
+	}
+
+	restored.mutex.Lock()
+	ttl := time.Until(restored.ttls["key1"])
+	restored.mutex.Unlock()
+	if ttl > time.Minute-20*time.Millisecond || ttl < 50*time.Second {
+		t.Error("remaining ttl should have been preserved. Got: ", ttl)
+	}
+}
+
+func TestLocalCacheRestoreOverMaxBytes(t *testing.T) {
+	snapshot := `[{"key":"k1","value":"aaaa","ttl":60000000000},{"key":"k2","value":"bbbb","ttl":60000000000},` +
+		`{"key":"k3","value":"cccc","ttl":60000000000},{"key":"k4","value":"` + strings.Repeat("d", 20) + `","ttl":60000000000}]`
+
+	cache, _ := NewLocalCacheWithOptions(&LocalCacheOptions{
+		MaxSize:  10,
+		TTL:      time.Minute,
+		MaxBytes: 10,
+		Sizer:    func(key string, value interface{}) int64 { return int64(len(value.(string))) },
+	})
+	count, err := cache.Restore(strings.NewReader(snapshot), JSONSnapshotCodec[string]{})
+	if err != nil || count != 2 {
+		t.Error("only the 2 most recently used entries fit within the size limit. Got: ", count, err)
+	}
+	if _, err := cache.Get("k1"); err != nil {
+		t.Error("k1 should have been restored. Got: ", err)
+	}
+	if _, err := cache.Get("k3"); err == nil {
+		t.Error("k3 should have been evicted while restoring")
+	}
+}
+
+func TestLocalCacheSnapshotErrors(t *testing.T) {
+	cache, _ := NewLocalCache(10, time.Minute)
+	if err := cache.Dump(&bytes.Buffer{}, nil); err == nil {
+		t.Error("a codec should be required")
+	}
+	if _, err := cache.Restore(strings.NewReader("[]"), nil); err == nil {
+		t.Error("a codec should be required")
+	}
+	if _, err := cache.Restore(strings.NewReader("{invalid"), JSONSnapshotCodec[string]{}); err == nil {
+		t.Error("decoding errors should be propagated")
+	}
+	if count, err := cache.Restore(strings.NewReader(`[{"key":"a","value":"b","ttl":-1}]`), JSONSnapshotCodec[string]{}); err != nil || count != 0 {
+		t.Error("entries without ttl left should be skipped. Got: ", count, err)
+	}
+}
@@ -0,0 +1,139 @@
+package cache
+
+import (
+	"sync/atomic"
+)
+
+// LocalCacheStats is a snapshot of a LocalCacheImpl's counters
+type LocalCacheStats struct {
+	Hits        int64
+	Misses      int64
+	Expirations int64 // lookups that found an expired entry
+	Evictions   int64 // entries dropped due to size limits or swept by the janitor. Deletions are not counted
+	Size        int
+	Bytes       int64
+}
+
+// HitRatio returns the fraction of lookups that found a valid entry
+func (s LocalCacheStats) HitRatio() float64 {
+	lookups := s.Hits + s.Misses + s.Expirations
+	if lookups == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(lookups)
+}
+
+// localStats holds the counters of a LocalCacheImpl, which are protected by the cache's lock
+type localStats struct {
+	hits        int64
+	misses      int64
+	expirations int64
+	evictions   int64
+}
+
+// Stats returns a snapshot of the cache's counters
+func (c *LocalCacheImpl) Stats() LocalCacheStats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return LocalCacheStats{
+		Hits:        c.stats.hits,
+		Misses:      c.stats.misses,
+		Expirations: c.stats.expirations,
+		Evictions:   c.stats.evictions,
+		Size:        c.lru.Len(),
+		Bytes:       c.bytes,
+	}
+}
+
+// ResetStats zeroes the cache's counters
+func (c *LocalCacheImpl) ResetStats() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.stats = localStats{}
+}
+
+// MultiLevelCacheStats is a snapshot of a MultiLevelCacheImpl's counters. Per-layer counters are indexed
+// like the layers (upper -> lower)
+type MultiLevelCacheStats struct {
+	LayerHits   []int64
+	LayerErrors []int64
+	Misses      int64 // lookups that didn't find the key in any layer
+	Backfills   int64 // successful writes to upper layers after a lookup
+}
+
+// multiLevelStats holds the counters of a MultiLevelCacheImpl, which are updated atomically
+type multiLevelStats struct {
+	layerHits   []int64
+	layerErrors []int64
+	misses      int64
+	backfills   int64
+}
+
+func newMultiLevelStats(layers int) *multiLevelStats {
+	return &multiLevelStats{layerHits: make([]int64, layers), layerErrors: make([]int64, layers)}
+}
+
+// the following methods are no-ops on a nil receiver, which keeps caches built without a constructor working
+
+func (s *multiLevelStats) hit(layer int) {
+	if s != nil {
+		atomic.AddInt64(&s.layerHits[layer], 1)
+	}
+}
+
+func (s *multiLevelStats) layerError(layer int) {
+	if s != nil {
+		atomic.AddInt64(&s.layerErrors[layer], 1)
+	}
+}
+
+func (s *multiLevelStats) miss() {
+	if s != nil {
+		atomic.AddInt64(&s.misses, 1)
+	}
+}
+
+func (s *multiLevelStats) backfill() {
+	if s != nil {
+		atomic.AddInt64(&s.backfills, 1)
+	}
+}
+
+func (s *multiLevelStats) snapshot() MultiLevelCacheStats {
+	if s == nil {
+		return MultiLevelCacheStats{}
+	}
+	snapshot := MultiLevelCacheStats{
+		LayerHits:   make([]int64, len(s.layerHits)),
+		LayerErrors: make([]int64, len(s.layerErrors)),
+		Misses:      atomic.LoadInt64(&s.misses),
+		Backfills:   atomic.LoadInt64(&s.backfills),
+	}
+	for idx := range s.layerHits {
+		snapshot.LayerHits[idx] = atomic.LoadInt64(&s.layerHits[idx])
+		snapshot.LayerErrors[idx] = atomic.LoadInt64(&s.layerErrors[idx])
+	}
+	return snapshot
+}
+
+func (s *multiLevelStats) reset() {
+	if s == nil {
+		return
+	}
+	for idx := range s.layerHits {
+		atomic.StoreInt64(&s.layerHits[idx], 0)
+		atomic.StoreInt64(&s.layerErrors[idx], 0)
+	}
+	atomic.StoreInt64(&s.misses, 0)
+	atomic.StoreInt64(&s.backfills, 0)
+}
+
+// Stats returns a snapshot of the cache's counters
+func (c *MultiLevelCacheImpl) Stats() MultiLevelCacheStats {
+	return c.stats.snapshot()
+}
+
+// ResetStats zeroes the cache's counters
+func (c *MultiLevelCacheImpl) ResetStats() {
+	c.stats.reset()
+}
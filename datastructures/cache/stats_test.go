@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLocalCacheStats(t *testing.T) {
+	cache, _ := NewLocalCacheWithOptions(&LocalCacheOptions{
+		MaxSize: 2,
+		TTL:     20 * time.Millisecond,
+		Sizer:   func(key string, value interface{}) int64 { return 10 },
+	})
+	cache.Set("key1", 1)
+	cache.Set("key2", 2)
+	cache.Get("key1")
+	cache.Get("key1")
+	cache.Get("missing")
+	cache.Set("key3", 3) // evicts key2
+	cache.Delete("key1")
+
+	stats := cache.Stats()
+	if stats.Hits != 2 || stats.Misses != 1 || stats.Evictions != 1 || stats.Size != 1 || stats.Bytes != 10 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if ratio := stats.HitRatio(); ratio < 0.66 || ratio > 0.67 {
+		t.Error("hit ratio should be 2/3. Is: ", ratio)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	cache.Get("key3")
+	if removed := cache.Sweep(); removed != 1 {
+		t.Error("key3 should have been swept. Removed: ", removed)
+	}
+	stats = cache.Stats()
+	if stats.Expirations != 1 || stats.Evictions != 2 || stats.Size != 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	cache.ResetStats()
+	if stats = cache.Stats(); stats != (LocalCacheStats{}) || stats.HitRatio() != 0 {
+		t.Errorf("stats should have been reset: %+v", stats)
+	}
+}
+
+func TestMultiLevelCacheStats(t *testing.T) {
+	failing := &LayerMock{
+		getCall: func(ctx context.Context, key string) (interface{}, error) { return nil, errors.New("someError") },
+		setCall: func(ctx context.Context, key string, value interface{}) error { return errors.New("someError") },
+	}
+	top, bottom := newMapLayer(nil), newMapLayer(map[string]interface{}{"key1": 1, "key2": 2})
+	cacheML, _ := NewMultiLevel([]MLCLayer{top, failing, bottom}, nil)
+
+	cacheML.Get(context.TODO(), "key1") // bottom hit, top backfilled, mid fails
+	cacheML.Get(context.TODO(), "key1") // top hit
+	cacheML.Get(context.TODO(), "key2")
+	cacheML.Get(context.TODO(), "missing")
+
+	stats := cacheML.Stats()
+	if stats.LayerHits[0] != 1 || stats.LayerHits[1] != 0 || stats.LayerHits[2] != 2 {
+		t.Error("unexpected layer hits: ", stats.LayerHits)
+	}
+	if stats.LayerErrors[0] != 0 || stats.LayerErrors[1] != 3 || stats.LayerErrors[2] != 0 {
+		t.Error("unexpected layer errors: ", stats.LayerErrors)
+	}
+	if stats.Misses != 1 || stats.Backfills != 2 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	cacheML.ResetStats()
+	stats = cacheML.Stats()
+	if stats.LayerHits[2] != 0 || stats.LayerErrors[1] != 0 || stats.Misses != 0 || stats.Backfills != 0 {
+		t.Errorf("stats should have been reset: %+v", stats)
+	}
+
+	unconstructed := MultiLevelCacheImpl{layers: []MLCLayer{top}}
+	unconstructed.Get(context.TODO(), "key1")
+	if stats := unconstructed.Stats(); stats.LayerHits != nil {
+		t.Errorf("caches built without a constructor should not track stats: %+v", stats)
+	}
+}
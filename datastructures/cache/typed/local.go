@@ -0,0 +1,194 @@
+// Package typed provides generics-based counterparts of the caches in the parent package
+package typed
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/splitio/go-toolkit/v5/datastructures/cache"
+)
+
+type node[K comparable, V any] struct {
+	key        K
+	value      V
+	expiration int64 // unix nanos. 0 means the entry never expires
+	prev       *node[K, V]
+	next       *node[K, V]
+}
+
+// LocalCache is an in-memory TTL & LRU cache with typed keys and values
+type LocalCache[K comparable, V any] struct {
+	ttl    time.Duration
+	maxLen int
+	items  map[K]*node[K, V]
+	head   *node[K, V] // most recently used
+	tail   *node[K, V] // least recently used
+	mutex  sync.Mutex
+}
+
+// NewLocalCache returns a new LocalCache instance of the specified size and default TTL. A TTL of 0 disables expiration
+func NewLocalCache[K comparable, V any](maxSize int, ttl time.Duration) (*LocalCache[K, V], error) {
+	if maxSize <= 0 {
+		return nil, fmt.Errorf("Cache size should be > 0. Is: %d", maxSize)
+	}
+	if ttl < 0 {
+		return nil, fmt.Errorf("TTL cannot be negative. Is: %s", ttl)
+	}
+
+	return &LocalCache[K, V]{
+		maxLen: maxSize,
+		ttl:    ttl,
+		items:  make(map[K]*node[K, V], maxSize),
+	}, nil
+}
+
+// Get retrieves an item if it exists and is not expired. Otherwise a *cache.Miss or *cache.Expired error is returned
+func (c *LocalCache[K, V]) Get(key K) (V, error) {
+	value, expiration, found, expired := c.get(key)
+	if !found {
+		return value, &cache.Miss{Where: "LOCAL", Key: fmt.Sprint(key)}
+	}
+	if expired {
+		var empty V
+		return empty, &cache.Expired{Key: fmt.Sprint(key), Value: value, When: time.Unix(0, expiration)}
+	}
+	return value, nil
+}
+
+// GetOrExpired retrieves an item even if it's expired, reporting whether it was found and whether it's expired.
+// Only non-expired items are promoted in the LRU
+func (c *LocalCache[K, V]) GetOrExpired(key K) (value V, found bool, expired bool) {
+	value, _, found, expired = c.get(key)
+	return value, found, expired
+}
+
+func (c *LocalCache[K, V]) get(key K) (value V, expiration int64, found bool, expired bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	n, ok := c.items[key]
+	if !ok {
+		return value, 0, false, false
+	}
+
+	if n.isExpired(time.Now().UnixNano()) {
+		return n.value, n.expiration, true, true
+	}
+
+	c.moveToFront(n)
+	return n.value, n.expiration, true, false
+}
+
+// Set adds or replaces an item using the default TTL. If the cache is full, the LRU item is removed
+func (c *LocalCache[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, c.ttl)
+}
+
+// SetWithTTL adds or replaces an item with a specific TTL. A TTL of 0 means the item never expires
+func (c *LocalCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	var expiration int64
+	if ttl > 0 {
+		expiration = time.Now().Add(ttl).UnixNano()
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if n, ok := c.items[key]; ok {
+		n.value = value
+		n.expiration = expiration
+		c.moveToFront(n)
+		return
+	}
+
+	// Drop the LRU item on the list before adding a new one, reusing its node to avoid an allocation.
+	var n *node[K, V]
+	if len(c.items) == c.maxLen {
+		n = c.tail
+		c.remove(n)
+		*n = node[K, V]{key: key, value: value, expiration: expiration}
+	} else {
+		n = &node[K, V]{key: key, value: value, expiration: expiration}
+	}
+	c.items[key] = n
+	c.pushFront(n)
+}
+
+// Delete removes an item, returning true if it was present
+func (c *LocalCache[K, V]) Delete(key K) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	n, ok := c.items[key]
+	if ok {
+		c.remove(n)
+	}
+	return ok
+}
+
+// Has returns true if the item is present and not expired. It does not affect the LRU order
+func (c *LocalCache[K, V]) Has(key K) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	n, ok := c.items[key]
+	return ok && !n.isExpired(time.Now().UnixNano())
+}
+
+// Len returns the number of items stored, including expired ones not yet evicted
+func (c *LocalCache[K, V]) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return len(c.items)
+}
+
+// Keys returns the keys of all stored items, from the most to the least recently used
+func (c *LocalCache[K, V]) Keys() []K {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	keys := make([]K, 0, len(c.items))
+	for n := c.head; n != nil; n = n.next {
+		keys = append(keys, n.key)
+	}
+	return keys
+}
+
+func (n *node[K, V]) isExpired(now int64) bool {
+	return n.expiration != 0 && now > n.expiration
+}
+
+func (c *LocalCache[K, V]) pushFront(n *node[K, V]) {
+	n.prev = nil
+	n.next = c.head
+	if c.head != nil {
+		c.head.prev = n
+	}
+	c.head = n
+	if c.tail == nil {
+		c.tail = n
+	}
+}
+
+func (c *LocalCache[K, V]) unlink(n *node[K, V]) {
+	if n.prev != nil {
+		n.prev.next = n.next
+	} else {
+		c.head = n.next
+	}
+	if n.next != nil {
+		n.next.prev = n.prev
+	} else {
+		c.tail = n.prev
+	}
+	n.prev, n.next = nil, nil
+}
+
+func (c *LocalCache[K, V]) moveToFront(n *node[K, V]) {
+	if c.head == n {
+		return
+	}
+	c.unlink(n)
+	c.pushFront(n)
+}
+
+func (c *LocalCache[K, V]) remove(n *node[K, V]) {
+	c.unlink(n)
+	delete(c.items, n.key)
+}
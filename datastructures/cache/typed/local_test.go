@@ -0,0 +1,171 @@
+package typed
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/splitio/go-toolkit/v5/datastructures/cache"
+)
+
+func TestLocalCache(t *testing.T) {
+	c, err := NewLocalCache[string, int](5, time.Second)
+	if err != nil {
+		t.Error("No error should have been returned. Got: ", err)
+	}
+
+	for i := 1; i <= 5; i++ {
+		c.Set(fmt.Sprintf("someKey%d", i), i)
+	}
+
+	for i := 1; i <= 5; i++ {
+		val, err := c.Get(fmt.Sprintf("someKey%d", i))
+		if err != nil {
+			t.Errorf("Getting value 'someKey%d', should not have raised an error. Got: %s", i, err)
+		}
+		if val != i {
+			t.Errorf("Value for key 'someKey%d' should be %d. Is %d", i, i, val)
+		}
+	}
+
+	c.Set("someKey6", 6)
+
+	// Oldest item (1) should have been removed
+	_, err = c.Get("someKey1")
+	var asMiss *cache.Miss
+	if !errors.As(err, &asMiss) {
+		t.Errorf("Error should be of type Miss. Is %T", err)
+	} else if asMiss.Key != "someKey1" || asMiss.Where != "LOCAL" {
+		t.Errorf("Incorrect data within the Miss error. Got: %+v", asMiss)
+	}
+
+	if c.Len() != 5 {
+		t.Error("Len should be 5. is: ", c.Len())
+	}
+
+	keys := c.Keys()
+	if len(keys) != 5 || keys[0] != "someKey6" || keys[4] != "someKey2" {
+		t.Error("Keys should be sorted from most to least recently used. Got: ", keys)
+	}
+
+	if !c.Has("someKey2") || c.Has("someKey1") {
+		t.Error("Has should only report present keys")
+	}
+
+	if !c.Delete("someKey2") || c.Delete("someKey2") || c.Has("someKey2") || c.Len() != 4 {
+		t.Error("someKey2 should have been deleted once")
+	}
+}
+
+func TestLocalCacheTTL(t *testing.T) {
+	c, _ := NewLocalCache[int64, int64](10, 50*time.Millisecond)
+	c.Set(1, 1)
+	c.SetWithTTL(2, 2, time.Hour)
+	c.SetWithTTL(3, 3, 0)
+
+	time.Sleep(100 * time.Millisecond)
+
+	_, err := c.Get(1)
+	var asExpired *cache.Expired
+	if !errors.As(err, &asExpired) {
+		t.Fatalf("Error should be of type Expired. Is %T", err)
+	}
+	if asExpired.Key != "1" || asExpired.Value != int64(1) || asExpired.When.After(time.Now()) {
+		t.Errorf("Incorrect data within the Expired error. Got: %+v", asExpired)
+	}
+
+	if val, found, expired := c.GetOrExpired(1); val != 1 || !found || !expired {
+		t.Error("expired value should be returned. Got: ", val, found, expired)
+	}
+	if _, found, expired := c.GetOrExpired(5); found || expired {
+		t.Error("missing keys should not be found")
+	}
+	if c.Has(1) {
+		t.Error("expired keys should not be reported by Has")
+	}
+
+	for _, key := range []int64{2, 3} {
+		if val, err := c.Get(key); err != nil || val != key {
+			t.Errorf("key %d should not have expired. Got: %d, %v", key, val, err)
+		}
+	}
+
+	noTTL, _ := NewLocalCache[int64, int64](10, 0)
+	noTTL.Set(1, 1)
+	if val, found, expired := noTTL.GetOrExpired(1); val != 1 || !found || expired {
+		t.Error("items should never expire without a ttl")
+	}
+
+	if _, err := NewLocalCache[int64, int64](0, 0); err == nil {
+		t.Error("a zero size should be rejected")
+	}
+}
+
+func TestLocalCacheConcurrency(t *testing.T) {
+	c, _ := NewLocalCache[int, int](100, time.Second)
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(offset int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				c.Set((offset+j)%200, j)
+				c.Get(j % 200)
+				c.Delete((offset + j) % 150)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if c.Len() > 100 || len(c.Keys()) != c.Len() {
+		t.Error("inconsistent cache size: ", c.Len(), len(c.Keys()))
+	}
+}
+
+func TestLocalCacheGetDoesNotAllocate(t *testing.T) {
+	c, _ := NewLocalCache[int64, int64](100, time.Minute)
+	for i := int64(0); i < 100; i++ {
+		c.Set(i, i)
+	}
+
+	key := int64(0)
+	allocs := testing.AllocsPerRun(1000, func() {
+		c.Get(key % 100)
+		key++
+	})
+	if allocs != 0 {
+		t.Error("Get should not allocate on hits. Allocs per run: ", allocs)
+	}
+}
+
+func benchmarkGet(b *testing.B, ttl time.Duration) {
+	c, _ := NewLocalCache[int64, int64](1000, ttl)
+	for i := int64(0); i < 1000; i++ {
+		c.Set(i, i)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Get(int64(i % 1000))
+	}
+}
+
+func BenchmarkLocalCacheGet(b *testing.B) {
+	benchmarkGet(b, 0)
+}
+
+func BenchmarkLocalCacheGetWithTTL(b *testing.B) {
+	benchmarkGet(b, time.Hour)
+}
+
+func BenchmarkLocalCacheSet(b *testing.B) {
+	c, _ := NewLocalCache[int64, int64](1000, time.Hour)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Set(int64(i%2000), int64(i))
+	}
+}
@@ -35,9 +35,11 @@ func TestInt64Cache(t *testing.T) {
 		t.Errorf("Getting value 'someKey1', should not have raised an error. Got: %s", err)
 	}
 
-	_, ok := err.(*Miss)
+	asMiss, ok := err.(*Miss)
 	if !ok {
 		t.Errorf("Error should be of type Miss. Is %T", err)
+	} else if asMiss.Key != 1 {
+		t.Error("Miss should include the key. Got: ", asMiss.Key)
 	}
 
 	if val != 0 {
@@ -55,7 +57,20 @@ func TestInt64Cache(t *testing.T) {
 		}
 	}
 
-	if len(c.items) != 5 {
-		t.Error("Items size should be 5. is: ", len(c.items))
+	if c.cache.Len() != 5 {
+		t.Error("Items size should be 5. is: ", c.cache.Len())
+	}
+}
+
+func BenchmarkInt64CacheGet(b *testing.B) {
+	c, _ := NewInt64Cache(1000)
+	for i := int64(0); i < 1000; i++ {
+		c.Set(i, i)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Get(int64(i % 1000))
 	}
 }
@@ -1,12 +1,19 @@
 package queuecache
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math"
 	"sync"
+	"time"
 )
 
+const defaultRefillInterval = 100 * time.Millisecond
+
+// ErrQueueFull is returned when pushing items into an overlay without room for them
+var ErrQueueFull = errors.New("queue is full")
+
 // RefillError struct to be returned when the refill function panics
 type RefillError struct {
 	OriginalPanic interface{}
@@ -28,29 +35,88 @@ func (e *MessagesDroppedError) Error() string {
 	)
 }
 
+// Options contains the parameters used to customize an overlay
+type Options struct {
+	// LowWaterMark enables the async prefetch mode: whenever the number of cached items drops below it, a
+	// refill is triggered in the background, and fetches return the available items without waiting for it.
+	// Refill errors are only reported to fetches that had to wait for the refill
+	LowWaterMark int
+	// RefillInterval is the minimum time between refills attempted by FetchWait while waiting for items.
+	// Defaults to 100ms
+	RefillInterval time.Duration
+	// VisibilityTimeout is how long items fetched with FetchLeased & FetchWaitLeased stay leased before being
+	// requeued, unless they're acknowledged. Defaults to 30s
+	VisibilityTimeout time.Duration
+}
+
 // InMemoryQueueCacheOverlay offers an in-memory queue that gets re-populated whenever it runs out of items
-type InMemoryQueueCacheOverlay struct {
-	maxSize      int
-	writeCursor  int
-	readCursor   int
-	queue        []interface{}
-	lock         sync.Mutex
-	refillCustom func(count int) ([]interface{}, error)
+type InMemoryQueueCacheOverlay = TypedInMemoryQueueCacheOverlay[interface{}]
+
+// TypedInMemoryQueueCacheOverlay is the generic version of InMemoryQueueCacheOverlay
+type TypedInMemoryQueueCacheOverlay[T any] struct {
+	maxSize        int
+	writeCursor    int
+	readCursor     int
+	queue          []T
+	lock           sync.Mutex
+	refillCustom   func(count int) ([]T, error)
+	lowWaterMark   int
+	refillInterval time.Duration
+	refilling      *refillCall
+	reserved       int
+	notify         chan struct{}
+
+	visibilityTimeout time.Duration
+	leases            map[uint64]lease[T]
+	nextLeaseID       uint64
+}
+
+// refillCall tracks an ongoing refill, so that concurrent fetches wait for the same one
+type refillCall struct {
+	done    chan struct{}
+	dropped int
+	err     error
 }
 
 // New creates a new InMemoryQueueCacheOverlay
 func New(maxSize int, refillFunc func(count int) ([]interface{}, error)) *InMemoryQueueCacheOverlay {
-	return &InMemoryQueueCacheOverlay{
-		queue:        make([]interface{}, maxSize),
-		maxSize:      maxSize,
-		writeCursor:  0,
-		readCursor:   0,
-		refillCustom: refillFunc,
+	return NewTyped(maxSize, refillFunc, nil)
+}
+
+// NewTyped creates a new TypedInMemoryQueueCacheOverlay
+func NewTyped[T any](maxSize int, refillFunc func(count int) ([]T, error), options *Options) *TypedInMemoryQueueCacheOverlay[T] {
+	if options == nil {
+		options = &Options{}
+	}
+	refillInterval := options.RefillInterval
+	if refillInterval <= 0 {
+		refillInterval = defaultRefillInterval
+	}
+	visibilityTimeout := options.VisibilityTimeout
+	if visibilityTimeout <= 0 {
+		visibilityTimeout = defaultVisibilityTimeout
+	}
+	return &TypedInMemoryQueueCacheOverlay[T]{
+		queue:             make([]T, maxSize),
+		maxSize:           maxSize,
+		writeCursor:       0,
+		readCursor:        0,
+		refillCustom:      refillFunc,
+		lowWaterMark:      options.LowWaterMark,
+		refillInterval:    refillInterval,
+		visibilityTimeout: visibilityTimeout,
 	}
 }
 
 // Count returns the number of cached items
-func (i *InMemoryQueueCacheOverlay) Count() int {
+func (i *TypedInMemoryQueueCacheOverlay[T]) Count() int {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	return i.count()
+}
+
+// count returns the number of cached items. Must be called with the lock held
+func (i *TypedInMemoryQueueCacheOverlay[T]) count() int {
 	if i.writeCursor == i.readCursor {
 		return 0
 	} else if i.writeCursor > i.readCursor {
@@ -59,7 +125,16 @@ func (i *InMemoryQueueCacheOverlay) Count() int {
 	return i.maxSize - (i.readCursor - i.writeCursor)
 }
 
-func (i *InMemoryQueueCacheOverlay) write(elem interface{}) error {
+// room returns how many items can be added, keeping space for leased items to be requeued and for the items
+// requested by an ongoing refill. Must be called with the lock held
+func (i *TypedInMemoryQueueCacheOverlay[T]) room() int {
+	if room := i.maxSize - 1 - i.count() - len(i.leases) - i.reserved; room > 0 {
+		return room
+	}
+	return 0
+}
+
+func (i *TypedInMemoryQueueCacheOverlay[T]) write(elem T) error {
 	if ((i.writeCursor + 1) % i.maxSize) == i.readCursor {
 		return errors.New("QUEUE_FULL")
 	}
@@ -69,17 +144,19 @@ func (i *InMemoryQueueCacheOverlay) write(elem interface{}) error {
 	return nil
 }
 
-func (i *InMemoryQueueCacheOverlay) read() (interface{}, error) {
+func (i *TypedInMemoryQueueCacheOverlay[T]) read() (T, error) {
+	var zero T
 	if i.readCursor == i.writeCursor {
-		return nil, errors.New("QUEUE_EMPTY")
+		return zero, errors.New("QUEUE_EMPTY")
 	}
 
 	toReturn := i.queue[i.readCursor]
+	i.queue[i.readCursor] = zero // don't retain references to fetched items
 	i.readCursor = (i.readCursor + 1) % i.maxSize
 	return toReturn, nil
 }
 
-func (i *InMemoryQueueCacheOverlay) refillWrapper(count int) (result []interface{}, err error) {
+func (i *TypedInMemoryQueueCacheOverlay[T]) refillWrapper(count int) (result []T, err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			result = nil
@@ -91,37 +168,245 @@ func (i *InMemoryQueueCacheOverlay) refillWrapper(count int) (result []interface
 
 }
 
-// Fetch items (will re-populate if necessary)
-func (i *InMemoryQueueCacheOverlay) Fetch(requestedCount int) ([]interface{}, error) {
-	defer i.lock.Unlock()
-	i.lock.Lock()
+// startRefill triggers a refill in the background, or returns the ongoing one. Returns nil if the overlay
+// has no refill function. Must be called with the lock held
+func (i *TypedInMemoryQueueCacheOverlay[T]) startRefill() *refillCall {
+	if i.refilling != nil {
+		return i.refilling
+	}
+	if i.refillCustom == nil {
+		return nil
+	}
 
-	dropped := 0
-	if i.Count() < requestedCount {
-		toAdd, err := i.refillWrapper(i.maxSize - i.Count() - 1)
-		if err != nil {
-			return nil, err
-		}
+	call := &refillCall{done: make(chan struct{})}
+	i.refilling = call
+	count := i.room()
+	i.reserved = count // so that producers can't take the room needed for items that may already be gone from the source
+	go func() {
+		toAdd, err := i.refillWrapper(count)
+
+		i.lock.Lock()
+		i.reserved = 0
 		for _, item := range toAdd {
-			err = i.write(item)
-			if err != nil {
-				dropped++
+			if i.room() == 0 || i.write(item) != nil {
+				call.dropped++
 			}
 		}
+		call.err = err
+		i.refilling = nil
+		i.signal()
+		i.lock.Unlock()
+		close(call.done)
+	}()
+	return call
+}
+
+// signal wakes up every FetchWait call waiting for items. Must be called with the lock held
+func (i *TypedInMemoryQueueCacheOverlay[T]) signal() {
+	if i.notify != nil {
+		close(i.notify)
+		i.notify = nil
+	}
+}
+
+// waitChan returns a channel that gets closed next time items are added. Must be called with the lock held
+func (i *TypedInMemoryQueueCacheOverlay[T]) waitChan() <-chan struct{} {
+	if i.notify == nil {
+		i.notify = make(chan struct{})
 	}
+	return i.notify
+}
 
-	toReturn := make([]interface{}, int(math.Min(float64(requestedCount), float64(i.Count()))))
+// refillIfLow triggers a background refill if the async mode is enabled and the queue is below the
+// low-water mark. Must be called with the lock held
+func (i *TypedInMemoryQueueCacheOverlay[T]) refillIfLow() {
+	if i.lowWaterMark > 0 && i.count() < i.lowWaterMark {
+		i.startRefill()
+	}
+}
+
+// take pops up to n items, leasing them if requested. Must be called with the lock held
+func (i *TypedInMemoryQueueCacheOverlay[T]) take(n int, leased bool) ([]T, []uint64) {
+	items := i.readUpTo(n)
+	if !leased {
+		return items, nil
+	}
+	return items, i.lease(items)
+}
+
+// readUpTo pops up to n items. Must be called with the lock held
+func (i *TypedInMemoryQueueCacheOverlay[T]) readUpTo(n int) []T {
+	toReturn := make([]T, int(math.Min(float64(n), float64(i.count()))))
 	for index := 0; index < len(toReturn); index++ {
 		elem, err := i.read()
 		if err != nil {
-			return toReturn[0:index], nil
+			return toReturn[0:index]
 		}
 		toReturn[index] = elem
 	}
+	return toReturn
+}
+
+// Fetch items (will re-populate if necessary)
+func (i *TypedInMemoryQueueCacheOverlay[T]) Fetch(requestedCount int) ([]T, error) {
+	return i.FetchWithContext(context.Background(), requestedCount)
+}
+
+// FetchWithContext fetches items like Fetch does, but stops waiting for a refill when the context is done.
+// An abandoned refill still completes in the background, and its items are kept for later fetches
+func (i *TypedInMemoryQueueCacheOverlay[T]) FetchWithContext(ctx context.Context, requestedCount int) ([]T, error) {
+	items, _, err := i.fetch(ctx, requestedCount, false)
+	return items, err
+}
+
+func (i *TypedInMemoryQueueCacheOverlay[T]) fetch(ctx context.Context, requestedCount int, leased bool) ([]T, []uint64, error) {
+	i.lock.Lock()
+	i.requeueExpired()
+	if i.lowWaterMark > 0 && i.count() > 0 {
+		toReturn, ids := i.take(requestedCount, leased)
+		i.refillIfLow()
+		i.lock.Unlock()
+		return toReturn, ids, nil
+	}
 
-	if dropped > 0 {
-		return toReturn, &MessagesDroppedError{MessagesDropped: dropped}
+	if i.count() >= requestedCount {
+		toReturn, ids := i.take(requestedCount, leased)
+		i.lock.Unlock()
+		return toReturn, ids, nil
 	}
-	return toReturn, nil
 
+	call := i.startRefill()
+	if call == nil {
+		toReturn, ids := i.take(requestedCount, leased)
+		i.lock.Unlock()
+		return toReturn, ids, nil
+	}
+	i.lock.Unlock()
+
+	select {
+	case <-call.done:
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+
+	if call.err != nil {
+		return nil, nil, call.err
+	}
+
+	i.lock.Lock()
+	toReturn, ids := i.take(requestedCount, leased)
+	i.refillIfLow()
+	i.lock.Unlock()
+
+	if call.dropped > 0 {
+		return toReturn, ids, &MessagesDroppedError{MessagesDropped: call.dropped}
+	}
+	return toReturn, ids, nil
+}
+
+// FetchWait blocks until n items are available and returns them. If maxWait elapses first, whatever is
+// available at that point is returned (possibly nothing). While waiting, refills are attempted at most once
+// per refill interval, and items pushed by producers are picked up as soon as they arrive
+func (i *TypedInMemoryQueueCacheOverlay[T]) FetchWait(ctx context.Context, n int, maxWait time.Duration) ([]T, error) {
+	items, _, err := i.fetchWait(ctx, n, n, maxWait, false)
+	return items, err
+}
+
+// FetchWaitAny waits like FetchWait does, but returns up to n items as soon as at least one is available
+func (i *TypedInMemoryQueueCacheOverlay[T]) FetchWaitAny(ctx context.Context, n int, maxWait time.Duration) ([]T, error) {
+	items, _, err := i.fetchWait(ctx, n, 1, maxWait, false)
+	return items, err
+}
+
+// fetchWait returns up to n items once at least minItems of them are available, or when maxWait elapses
+func (i *TypedInMemoryQueueCacheOverlay[T]) fetchWait(ctx context.Context, n int, minItems int, maxWait time.Duration, leased bool) ([]T, []uint64, error) {
+	deadline := time.NewTimer(maxWait)
+	defer deadline.Stop()
+
+	var lastRefill time.Time
+	var pending *refillCall
+	for {
+		i.lock.Lock()
+		i.requeueExpired()
+		if pending != nil && i.refilling != pending { // the refill we were waiting for has completed
+			if pending.err != nil {
+				i.lock.Unlock()
+				return nil, nil, pending.err
+			}
+			pending = nil
+		}
+
+		if i.count() >= minItems {
+			toReturn, ids := i.take(n, leased)
+			i.refillIfLow()
+			i.lock.Unlock()
+			return toReturn, ids, nil
+		}
+
+		// wake up to retry the refill, or when the next lease expires, whatever happens first
+		wake := time.Duration(-1)
+		var refillDone <-chan struct{}
+		if wait := i.refillInterval - time.Since(lastRefill); i.refilling != nil || wait <= 0 {
+			if pending = i.startRefill(); pending != nil {
+				refillDone = pending.done
+				lastRefill = time.Now()
+			}
+		} else if i.refillCustom != nil {
+			wake = wait
+		}
+		if expiry, ok := i.nextLeaseExpiry(); ok && (wake < 0 || expiry < wake) {
+			wake = expiry
+		}
+
+		var retry *time.Timer
+		var retryC <-chan time.Time
+		if wake >= 0 {
+			retry = time.NewTimer(wake)
+			retryC = retry.C
+		}
+		notify := i.waitChan()
+		i.lock.Unlock()
+
+		select {
+		case <-notify:
+		case <-retryC:
+		case <-refillDone:
+		case <-deadline.C:
+			i.lock.Lock()
+			defer i.lock.Unlock()
+			i.requeueExpired()
+			toReturn, ids := i.take(n, leased)
+			return toReturn, ids, nil
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+
+		if retry != nil {
+			retry.Stop()
+		}
+	}
+}
+
+// Push adds items to the overlay, waking up any consumer waiting for them. Returns the number of items
+// added, along with ErrQueueFull if some of them didn't fit. The room requested by an ongoing refill is
+// not available to producers until the refill completes
+func (i *TypedInMemoryQueueCacheOverlay[T]) Push(items ...T) (int, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	pushed := 0
+	for _, item := range items {
+		if i.room() == 0 || i.write(item) != nil {
+			break
+		}
+		pushed++
+	}
+
+	if pushed > 0 {
+		i.signal()
+	}
+	if pushed < len(items) {
+		return pushed, ErrQueueFull
+	}
+	return pushed, nil
 }
@@ -1,9 +1,13 @@
 package queuecache
 
 import (
+	"context"
 	"errors"
 	"math"
+	"strconv"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestCacheBasicUsage(t *testing.T) {
@@ -159,3 +163,229 @@ func TestCountWorksProperly(t *testing.T) {
 		t.Error("Count should be 69 and is: ", cache.Count())
 	}
 }
+
+func TestTypedOverlay(t *testing.T) {
+	next := 0
+	myCache := NewTyped(10, func(count int) ([]string, error) {
+		toReturn := make([]string, count)
+		for index := range toReturn {
+			toReturn[index] = strconv.Itoa(next)
+			next++
+		}
+		return toReturn, nil
+	}, nil)
+
+	items, err := myCache.Fetch(3)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(items) != 3 || items[0] != "0" || items[2] != "2" {
+		t.Error("unexpected items: ", items)
+	}
+	if myCache.Count() != 6 {
+		t.Error("the remaining refilled items should be cached. Count: ", myCache.Count())
+	}
+}
+
+func TestAsyncPrefetch(t *testing.T) {
+	var refills int64
+	release := make(chan struct{}, 10)
+	next := 0
+	myCache := NewTyped(10, func(count int) ([]int, error) {
+		if atomic.AddInt64(&refills, 1) > 1 {
+			<-release
+		}
+		toReturn := make([]int, count)
+		for index := range toReturn {
+			toReturn[index] = next
+			next++
+		}
+		return toReturn, nil
+	}, &Options{LowWaterMark: 5})
+
+	// First fetch has nothing cached, so it has to wait for the refill
+	if items, err := myCache.Fetch(3); err != nil || len(items) != 3 || items[0] != 0 {
+		t.Error("unexpected result: ", items, err)
+	}
+
+	// Dropping below the low-water mark triggers a background refill that doesn't block fetches
+	if items, err := myCache.Fetch(3); err != nil || len(items) != 3 || items[0] != 3 {
+		t.Error("unexpected result: ", items, err)
+	}
+	if items, err := myCache.Fetch(5); err != nil || len(items) != 3 || items[0] != 6 {
+		t.Error("available items should be returned without waiting for the refill. Got: ", items, err)
+	}
+
+	release <- struct{}{}
+	time.Sleep(20 * time.Millisecond)
+	if atomic.LoadInt64(&refills) != 2 {
+		t.Error("a single background refill should have been triggered. Refills: ", atomic.LoadInt64(&refills))
+	}
+
+	items, err := myCache.Fetch(20)
+	if err != nil || len(items) != 6 || items[0] != 9 {
+		t.Error("background refill should have repopulated the queue. Got: ", items, err)
+	}
+	close(release)
+}
+
+func TestFetchWithContext(t *testing.T) {
+	release := make(chan struct{})
+	myCache := NewTyped(10, func(count int) ([]int, error) {
+		<-release
+		return []int{1, 2, 3}, nil
+	}, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := myCache.FetchWithContext(ctx, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Error("fetch should honor the context deadline. Got: ", err)
+	}
+
+	close(release)
+	time.Sleep(20 * time.Millisecond)
+	if myCache.Count() != 3 {
+		t.Error("the abandoned refill should have completed in the background. Count: ", myCache.Count())
+	}
+	if items, err := myCache.FetchWithContext(context.Background(), 3); err != nil || len(items) != 3 {
+		t.Error("unexpected result: ", items, err)
+	}
+}
+
+func TestPush(t *testing.T) {
+	myCache := NewTyped[int](5, nil, nil)
+	if pushed, err := myCache.Push(1, 2, 3); pushed != 3 || err != nil {
+		t.Error("items should have been pushed. Got: ", pushed, err)
+	}
+	if pushed, err := myCache.Push(4, 5, 6); pushed != 1 || err != ErrQueueFull {
+		t.Error("items beyond the capacity should be rejected. Got: ", pushed, err)
+	}
+
+	if items, err := myCache.Fetch(10); err != nil || len(items) != 4 || items[0] != 1 || items[3] != 4 {
+		t.Error("pushed items should be fetched in order. Got: ", items, err)
+	}
+	if items, err := myCache.Fetch(1); err != nil || len(items) != 0 {
+		t.Error("an empty overlay without refill function should return nothing. Got: ", items, err)
+	}
+}
+
+func TestPushDuringRefill(t *testing.T) {
+	release := make(chan struct{})
+	myCache := NewTyped(6, func(count int) ([]int, error) {
+		<-release
+		toReturn := make([]int, count)
+		for index := range toReturn {
+			toReturn[index] = index
+		}
+		return toReturn, nil
+	}, nil)
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := myCache.Fetch(1)
+		result <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	if pushed, err := myCache.Push(7, 8); pushed != 0 || err != ErrQueueFull {
+		t.Error("the room reserved by the ongoing refill should not be available to producers. Got: ", pushed, err)
+	}
+
+	close(release)
+	if err := <-result; err != nil {
+		t.Error("no refilled items should have been dropped. Got: ", err)
+	}
+	if myCache.Count() != 4 {
+		t.Error("the remaining refilled items should be cached. Count: ", myCache.Count())
+	}
+	if pushed, err := myCache.Push(7); pushed != 1 || err != nil {
+		t.Error("room should be released once the refill completes. Got: ", pushed, err)
+	}
+}
+
+func TestFetchWait(t *testing.T) {
+	myCache := NewTyped[int](10, nil, nil)
+	go func() {
+		for index := 0; index < 3; index++ {
+			time.Sleep(10 * time.Millisecond)
+			myCache.Push(index)
+		}
+	}()
+
+	before := time.Now()
+	items, err := myCache.FetchWait(context.Background(), 3, time.Second)
+	if err != nil || len(items) != 3 {
+		t.Error("should have waited for 3 items. Got: ", items, err)
+	}
+	if elapsed := time.Since(before); elapsed > 500*time.Millisecond {
+		t.Error("should have returned as soon as the items were available. Took: ", elapsed)
+	}
+
+	myCache.Push(1)
+	before = time.Now()
+	items, err = myCache.FetchWait(context.Background(), 3, 30*time.Millisecond)
+	if err != nil || len(items) != 1 || time.Since(before) < 30*time.Millisecond {
+		t.Error("available items should be returned once the max wait elapses. Got: ", items, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := myCache.FetchWait(ctx, 1, time.Second); !errors.Is(err, context.DeadlineExceeded) {
+		t.Error("FetchWait should honor the context. Got: ", err)
+	}
+}
+
+func TestFetchWaitAny(t *testing.T) {
+	myCache := NewTyped[int](10, nil, nil)
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		myCache.Push(1)
+	}()
+
+	before := time.Now()
+	items, err := myCache.FetchWaitAny(context.Background(), 5, time.Second)
+	if err != nil || len(items) != 1 || items[0] != 1 {
+		t.Error("should have returned the first pushed item. Got: ", items, err)
+	}
+	if elapsed := time.Since(before); elapsed > 500*time.Millisecond {
+		t.Error("should have returned as soon as an item was available. Took: ", elapsed)
+	}
+
+	myCache.Push(2, 3, 4)
+	if items, err := myCache.FetchWaitAny(context.Background(), 2, time.Second); err != nil || len(items) != 2 || items[0] != 2 {
+		t.Error("at most n items should be returned. Got: ", items, err)
+	}
+
+	before = time.Now()
+	items, err = myCache.FetchWaitAny(context.Background(), 5, time.Second)
+	if err != nil || len(items) != 1 || items[0] != 4 || time.Since(before) > 500*time.Millisecond {
+		t.Error("available items should be returned without waiting. Got: ", items, err)
+	}
+}
+
+func TestFetchWaitRefills(t *testing.T) {
+	var refills int64
+	myCache := NewTyped(10, func(count int) ([]int, error) {
+		if atomic.AddInt64(&refills, 1) < 3 {
+			return nil, nil
+		}
+		return []int{1, 2}, nil
+	}, &Options{RefillInterval: 20 * time.Millisecond})
+
+	before := time.Now()
+	items, err := myCache.FetchWait(context.Background(), 2, time.Second)
+	if err != nil || len(items) != 2 {
+		t.Error("items should have been refilled. Got: ", items, err)
+	}
+	if elapsed := time.Since(before); elapsed < 40*time.Millisecond {
+		t.Error("empty refills should be retried once per refill interval. Took: ", elapsed)
+	}
+	if atomic.LoadInt64(&refills) != 3 {
+		t.Error("refill should have been called 3 times. Was: ", atomic.LoadInt64(&refills))
+	}
+
+	failing := NewTyped(10, func(count int) ([]int, error) { return nil, errors.New("someError") }, nil)
+	if _, err := failing.FetchWait(context.Background(), 1, time.Second); err == nil {
+		t.Error("refill errors should be propagated")
+	}
+}
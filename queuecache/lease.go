@@ -0,0 +1,154 @@
+package queuecache
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+const defaultVisibilityTimeout = 30 * time.Second
+
+// ErrLeaseNotFound is returned when acknowledging an item that's not leased, usually because its lease
+// expired and the item was requeued
+var ErrLeaseNotFound = errors.New("lease not found")
+
+// LeasedItem is an item fetched in at-least-once mode. It has to be acknowledged once processed,
+// otherwise it's requeued when its lease expires
+type LeasedItem[T any] struct {
+	LeaseID uint64
+	Item    T
+}
+
+type lease[T any] struct {
+	item    T
+	expires time.Time
+}
+
+// FetchLeased fetches items like FetchWithContext does, but keeps them leased until they're acknowledged
+func (i *TypedInMemoryQueueCacheOverlay[T]) FetchLeased(ctx context.Context, requestedCount int) ([]LeasedItem[T], error) {
+	items, ids, err := i.fetch(ctx, requestedCount, true)
+	return toLeasedItems(items, ids), err
+}
+
+// FetchWaitLeased waits for items like FetchWait does, but keeps them leased until they're acknowledged
+func (i *TypedInMemoryQueueCacheOverlay[T]) FetchWaitLeased(ctx context.Context, n int, maxWait time.Duration) ([]LeasedItem[T], error) {
+	items, ids, err := i.fetchWait(ctx, n, n, maxWait, true)
+	return toLeasedItems(items, ids), err
+}
+
+// Ack releases the leases of successfully processed items. ErrLeaseNotFound is returned if any of them
+// is not leased anymore, which means that the item has been (or will be) delivered again
+func (i *TypedInMemoryQueueCacheOverlay[T]) Ack(leaseIDs ...uint64) error {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	var err error
+	for _, id := range leaseIDs {
+		if _, ok := i.leases[id]; !ok {
+			err = ErrLeaseNotFound
+			continue
+		}
+		delete(i.leases, id)
+	}
+	return err
+}
+
+// Nack releases the leases of items that failed to be processed, requeueing them immediately at the
+// front of the queue
+func (i *TypedInMemoryQueueCacheOverlay[T]) Nack(leaseIDs ...uint64) error {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	var err error
+	toRequeue := make([]uint64, 0, len(leaseIDs))
+	for _, id := range leaseIDs {
+		if _, ok := i.leases[id]; !ok {
+			err = ErrLeaseNotFound
+			continue
+		}
+		toRequeue = append(toRequeue, id)
+	}
+	i.requeue(toRequeue)
+	return err
+}
+
+// Leased returns the number of items fetched but not yet acknowledged
+func (i *TypedInMemoryQueueCacheOverlay[T]) Leased() int {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	return len(i.leases)
+}
+
+// lease registers leases for the supplied items. Must be called with the lock held
+func (i *TypedInMemoryQueueCacheOverlay[T]) lease(items []T) []uint64 {
+	if i.leases == nil {
+		i.leases = make(map[uint64]lease[T])
+	}
+
+	expires := time.Now().Add(i.visibilityTimeout)
+	ids := make([]uint64, len(items))
+	for index, item := range items {
+		i.nextLeaseID++
+		ids[index] = i.nextLeaseID
+		i.leases[i.nextLeaseID] = lease[T]{item: item, expires: expires}
+	}
+	return ids
+}
+
+// requeueExpired puts back in the queue the items whose lease expired. Must be called with the lock held
+func (i *TypedInMemoryQueueCacheOverlay[T]) requeueExpired() {
+	now := time.Now()
+	var expired []uint64
+	for id, l := range i.leases {
+		if now.After(l.expires) {
+			expired = append(expired, id)
+		}
+	}
+	i.requeue(expired)
+}
+
+// requeue puts the leased items at the front of the queue, preserving the order in which they were
+// fetched. Must be called with the lock held
+func (i *TypedInMemoryQueueCacheOverlay[T]) requeue(ids []uint64) {
+	if len(ids) == 0 {
+		return
+	}
+
+	sort.Slice(ids, func(a, b int) bool { return ids[a] > ids[b] })
+	for _, id := range ids {
+		// room for leased items is always reserved, so this cannot overflow
+		i.readCursor = (i.readCursor - 1 + i.maxSize) % i.maxSize
+		i.queue[i.readCursor] = i.leases[id].item
+		delete(i.leases, id)
+	}
+	i.signal()
+}
+
+// nextLeaseExpiry returns the time until the next lease expires. Must be called with the lock held
+func (i *TypedInMemoryQueueCacheOverlay[T]) nextLeaseExpiry() (time.Duration, bool) {
+	var next time.Time
+	for _, l := range i.leases {
+		if next.IsZero() || l.expires.Before(next) {
+			next = l.expires
+		}
+	}
+	if next.IsZero() {
+		return 0, false
+	}
+	if remaining := time.Until(next); remaining > 0 {
+		return remaining, true
+	}
+	return 0, true
+}
+
+func toLeasedItems[T any](items []T, ids []uint64) []LeasedItem[T] {
+	if items == nil {
+		return nil
+	}
+	leased := make([]LeasedItem[T], len(items))
+	for index := range items {
+		leased[index] = LeasedItem[T]{LeaseID: ids[index], Item: items[index]}
+	}
+	return leased
+}
@@ -0,0 +1,88 @@
+package queuecache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLeases(t *testing.T) {
+	myCache := NewTyped[int](10, nil, &Options{VisibilityTimeout: 30 * time.Millisecond})
+	myCache.Push(1, 2, 3, 4)
+
+	leased, err := myCache.FetchLeased(context.Background(), 3)
+	if err != nil || len(leased) != 3 || leased[0].Item != 1 || leased[2].Item != 3 {
+		t.Error("unexpected result: ", leased, err)
+	}
+	if myCache.Leased() != 3 || myCache.Count() != 1 {
+		t.Error("fetched items should be leased. Leased: ", myCache.Leased())
+	}
+
+	if err := myCache.Ack(leased[0].LeaseID); err != nil {
+		t.Error("No error should have been returned. Got: ", err)
+	}
+	if err := myCache.Ack(leased[0].LeaseID); err != ErrLeaseNotFound {
+		t.Error("acking twice should fail. Got: ", err)
+	}
+
+	// Nacked items are redelivered before the rest
+	if err := myCache.Nack(leased[2].LeaseID); err != nil {
+		t.Error("No error should have been returned. Got: ", err)
+	}
+	if items, _ := myCache.FetchLeased(context.Background(), 1); len(items) != 1 || items[0].Item != 3 {
+		t.Error("nacked item should have been requeued at the front. Got: ", items)
+	}
+
+	// Un-acked items return to the queue once the visibility timeout passes, in the original order
+	time.Sleep(40 * time.Millisecond)
+	items, _ := myCache.FetchLeased(context.Background(), 10)
+	if len(items) != 3 || items[0].Item != 2 || items[1].Item != 3 || items[2].Item != 4 {
+		t.Error("expired leases should have been requeued. Got: ", items)
+	}
+	if err := myCache.Ack(leased[1].LeaseID); err != ErrLeaseNotFound {
+		t.Error("acking an expired lease should fail. Got: ", err)
+	}
+}
+
+func TestLeasesReserveRoom(t *testing.T) {
+	myCache := NewTyped[int](5, nil, nil)
+	myCache.Push(1, 2, 3, 4)
+	leased, _ := myCache.FetchLeased(context.Background(), 2)
+
+	if pushed, err := myCache.Push(5, 6); pushed != 0 || err != ErrQueueFull {
+		t.Error("room for leased items should be reserved. Got: ", pushed, err)
+	}
+
+	myCache.Nack(leased[0].LeaseID, leased[1].LeaseID)
+	if items, _ := myCache.Fetch(10); len(items) != 4 || items[0] != 1 || items[3] != 4 {
+		t.Error("every item should have been requeued in order. Got: ", items)
+	}
+
+	refilled := NewTyped(5, func(count int) ([]int, error) {
+		if count != 2 {
+			t.Error("refills should only ask for the items that fit. Count: ", count)
+		}
+		return []int{1, 2}, nil
+	}, nil)
+	refilled.Push(1, 2)
+	refilled.FetchLeased(context.Background(), 2)
+	refilled.Fetch(1)
+}
+
+func TestFetchWaitLeased(t *testing.T) {
+	myCache := NewTyped[int](10, nil, &Options{VisibilityTimeout: 20 * time.Millisecond})
+	myCache.Push(1)
+	if items, _ := myCache.FetchWaitLeased(context.Background(), 1, time.Second); len(items) != 1 {
+		t.Error("unexpected result: ", items)
+	}
+
+	// Waiting consumers should get the item back as soon as its lease expires
+	before := time.Now()
+	items, err := myCache.FetchWaitLeased(context.Background(), 1, time.Second)
+	if err != nil || len(items) != 1 || items[0].Item != 1 {
+		t.Error("expired item should have been redelivered. Got: ", items, err)
+	}
+	if elapsed := time.Since(before); elapsed > 500*time.Millisecond {
+		t.Error("redelivery should happen when the lease expires. Took: ", elapsed)
+	}
+}
@@ -0,0 +1,85 @@
+// Package redisqueue provides refill functions that let queuecache overlays sit directly on redis lists
+package redisqueue
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/splitio/go-toolkit/v5/redis"
+)
+
+// popScript atomically pops up to ARGV[1] items from the head of the list
+const popScript = `
+local items = redis.call('LRANGE', KEYS[1], 0, ARGV[1] - 1)
+if #items > 0 then
+	redis.call('LTRIM', KEYS[1], #items, -1)
+end
+return items`
+
+// Deserializer converts a raw list element into an item
+type Deserializer[T any] func(raw string) (T, error)
+
+// StringDeserializer returns list elements as-is
+func StringDeserializer(raw string) (string, error) {
+	return raw, nil
+}
+
+// JSONDeserializer decodes list elements as JSON documents
+func JSONDeserializer[T any](raw string) (T, error) {
+	var item T
+	err := json.Unmarshal([]byte(raw), &item)
+	return item, err
+}
+
+// NewRefillFunc returns a refill function for queuecache.NewTyped that pops items from the redis list stored
+// at key. Since popped elements cannot be put back, elements that fail to be deserialized are dropped, and
+// reported in the returned error along with the rest of the items
+func NewRefillFunc[T any](client *redis.PrefixedRedisClient, key string, deserialize Deserializer[T]) (func(count int) ([]T, error), error) {
+	if client == nil {
+		return nil, errors.New("a redis client is required")
+	}
+	if deserialize == nil {
+		return nil, errors.New("a deserializer is required")
+	}
+
+	return func(count int) ([]T, error) {
+		if count <= 0 {
+			return nil, nil
+		}
+
+		raw, err := client.EvalMultiPrefixed(popScript, []string{key}, count)
+		if err != nil {
+			return nil, err
+		}
+
+		items := make([]T, 0, len(raw))
+		failed := 0
+		var firstErr error
+		for _, element := range raw {
+			asString, ok := element.(string)
+			if !ok {
+				failed++
+				if firstErr == nil {
+					firstErr = fmt.Errorf("unexpected list element type: %T", element)
+				}
+				continue
+			}
+
+			item, err := deserialize(asString)
+			if err != nil {
+				failed++
+				if firstErr == nil {
+					firstErr = err
+				}
+				continue
+			}
+			items = append(items, item)
+		}
+
+		if failed > 0 {
+			return items, fmt.Errorf("%d items dropped due to deserialization errors: %w", failed, firstErr)
+		}
+		return items, nil
+	}, nil
+}
@@ -0,0 +1,137 @@
+package redisqueue
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/splitio/go-toolkit/v5/queuecache"
+	"github.com/splitio/go-toolkit/v5/redis"
+	"github.com/splitio/go-toolkit/v5/redis/mocks"
+)
+
+type event struct {
+	ID int `json:"id"`
+}
+
+// listMock emulates the pop script on top of an in-memory list
+func listMock(t *testing.T, expectedKey string, list *[]interface{}) *mocks.MockClient {
+	return &mocks.MockClient{
+		EvalCall: func(script string, keys []string, args ...interface{}) redis.Result {
+			if script != popScript {
+				t.Error("the pop script should have been used")
+			}
+			if len(keys) != 1 || keys[0] != expectedKey {
+				t.Error("wrong keys: ", keys)
+			}
+			count := args[0].(int)
+			if count > len(*list) {
+				count = len(*list)
+			}
+			popped := (*list)[:count]
+			*list = (*list)[count:]
+			return &mocks.MockResultOutput{MultiInterfaceCall: func() ([]interface{}, error) { return popped, nil }}
+		},
+	}
+}
+
+func TestRefillFunc(t *testing.T) {
+	list := []interface{}{`{"id":1}`, `{"id":2}`, `{"id":3}`, `{"id":4}`}
+	client, _ := redis.NewPrefixedRedisClient(listMock(t, "prefix.events", &list), "prefix")
+	refill, err := NewRefillFunc(client, "events", JSONDeserializer[event])
+	if err != nil {
+		t.Error("No error should have been returned. Got: ", err)
+	}
+
+	overlay := queuecache.NewTyped(4, refill, nil)
+	items, err := overlay.Fetch(2)
+	if err != nil || len(items) != 2 || items[0].ID != 1 || items[1].ID != 2 {
+		t.Error("unexpected result: ", items, err)
+	}
+	if overlay.Count() != 1 || len(list) != 1 {
+		t.Error("only the items that fit should have been popped. Remaining: ", len(list))
+	}
+
+	items, _ = overlay.Fetch(5)
+	if len(items) != 2 || items[1].ID != 4 || len(list) != 0 {
+		t.Error("unexpected result: ", items)
+	}
+
+	if items, err := refill(0); items != nil || err != nil {
+		t.Error("nothing should be popped when there's no room. Got: ", items, err)
+	}
+}
+
+func TestRefillFuncErrors(t *testing.T) {
+	if _, err := NewRefillFunc[string](nil, "events", StringDeserializer); err == nil {
+		t.Error("a client should be required")
+	}
+
+	list := []interface{}{`{"id":1}`, `invalid`, int64(3)}
+	client, _ := redis.NewPrefixedRedisClient(listMock(t, "events", &list), "")
+	if _, err := NewRefillFunc[event](client, "events", nil); err == nil {
+		t.Error("a deserializer should be required")
+	}
+
+	refill, _ := NewRefillFunc(client, "events", JSONDeserializer[event])
+	items, err := refill(10)
+	if len(items) != 1 || items[0].ID != 1 {
+		t.Error("valid items should be returned. Got: ", items)
+	}
+	if err == nil || !strings.HasPrefix(err.Error(), "2 items dropped") {
+		t.Error("deserialization failures should be reported. Got: ", err)
+	}
+
+	failing := &mocks.MockClient{
+		EvalCall: func(script string, keys []string, args ...interface{}) redis.Result {
+			return &mocks.MockResultOutput{MultiInterfaceCall: func() ([]interface{}, error) { return nil, errors.New("someError") }}
+		},
+	}
+	client, _ = redis.NewPrefixedRedisClient(failing, "")
+	refillStrings, _ := NewRefillFunc(client, "events", StringDeserializer)
+	if _, err := refillStrings(10); err == nil || err.Error() != "someError" {
+		t.Error("redis errors should be propagated. Got: ", err)
+	}
+}
+
+func TestRefillFuncRedis(t *testing.T) {
+	client, err := redis.NewClient(&redis.UniversalOptions{})
+	if err != nil {
+		t.Fatal("No error should have been returned. Got: ", err)
+	}
+	prefixed, _ := redis.NewPrefixedRedisClient(client, "utest")
+	prefixed.Del("refill")
+	defer prefixed.Del("refill")
+	prefixed.RPush("refill", "e1", "e2", "e3", "e4", "e5")
+
+	refill, _ := NewRefillFunc(prefixed, "refill", StringDeserializer)
+	items, err := refill(3)
+	if err != nil {
+		t.Error("No error should have been returned. Got: ", err)
+	}
+	if strings.Join(items, ",") != "e1,e2,e3" {
+		t.Error("the first 3 items should have been popped. Got: ", items)
+	}
+
+	// the script must operate on the prefixed key
+	if exists := client.Exists("utest.refill").Int(); exists != 1 {
+		t.Error("items should be popped from the prefixed key")
+	}
+	remaining, _ := prefixed.LRange("refill", 0, -1)
+	if strings.Join(remaining, ",") != "e4,e5" {
+		t.Error("only the items not popped should remain. Got: ", remaining)
+	}
+
+	items, _ = refill(10)
+	if strings.Join(items, ",") != "e4,e5" {
+		t.Error("the remaining items should have been popped. Got: ", items)
+	}
+	if length, _ := prefixed.LLen("refill"); length != 0 {
+		t.Error("list should be empty. Length: ", length)
+	}
+
+	items, err = refill(10)
+	if err != nil || len(items) != 0 {
+		t.Error("popping from an empty list should return no items. Got: ", items, err)
+	}
+}
@@ -1,6 +1,7 @@
 package mocks
 
 import (
+	"context"
 	"time"
 
 	"github.com/splitio/go-toolkit/v5/redis"
@@ -13,6 +14,7 @@ type MockResultOutput struct {
 	StringCall          func() string
 	BoolCall            func() bool
 	DurationCall        func() time.Duration
+	FloatCall           func() float64
 	ResultCall          func() (int64, error)
 	ResultStringCall    func() (string, error)
 	MultiCall           func() ([]string, error)
@@ -45,6 +47,11 @@ func (m *MockResultOutput) Duration() time.Duration {
 	return m.DurationCall()
 }
 
+// Float mocks Float
+func (m *MockResultOutput) Float() float64 {
+	return m.FloatCall()
+}
+
 // Result mocks Result
 func (m *MockResultOutput) Result() (int64, error) {
 	return m.ResultCall()
@@ -72,19 +79,40 @@ func (m *MockResultOutput) MapStringString() (map[string]string, error) {
 
 // MpockPipeline  impl
 type MockPipeline struct {
-	LRangeCall   func(key string, start, stop int64)
-	LTrimCall    func(key string, start, stop int64)
-	LLenCall     func(key string)
-	HIncrByCall  func(key string, field string, value int64)
-	HLenCall     func(key string)
-	SetCall      func(key string, value interface{}, expiration time.Duration)
-	IncrCall     func(key string)
-	DecrCall     func(key string)
-	SAddCall     func(key string, members ...interface{})
-	SRemCall     func(key string, members ...interface{})
-	SMembersCall func(key string)
-	DelCall      func(keys ...string)
-	ExecCall     func() ([]redis.Result, error)
+	LRangeCall        func(key string, start, stop int64)
+	LTrimCall         func(key string, start, stop int64)
+	LLenCall          func(key string)
+	HIncrByCall       func(key string, field string, value int64)
+	HLenCall          func(key string)
+	SetCall           func(key string, value interface{}, expiration time.Duration)
+	IncrCall          func(key string)
+	DecrCall          func(key string)
+	SAddCall          func(key string, members ...interface{})
+	SRemCall          func(key string, members ...interface{})
+	SMembersCall      func(key string)
+	DelCall           func(keys ...string)
+	ExecCall          func() ([]redis.Result, error)
+	WithContextCall   func(ctx context.Context) redis.Pipeline
+	HGetCall          func(key string, field string)
+	HMGetCall         func(key string, fields ...string)
+	HMSetCall         func(key string, values map[string]interface{})
+	HDelCall          func(key string, fields ...string)
+	HExistsCall       func(key string, field string)
+	HKeysCall         func(key string)
+	ZAddCall          func(key string, members ...redis.Z)
+	ZRangeCall        func(key string, start, stop int64)
+	ZRangeByScoreCall func(key string, min, max string, offset, count int64)
+	ZRemCall          func(key string, members ...interface{})
+	ZScoreCall        func(key string, member string)
+	LPopCall          func(key string)
+	RPopCall          func(key string)
+	BLPopCall         func(timeout time.Duration, keys ...string)
+	LPushCall         func(key string, values ...interface{})
+	LRemCall          func(key string, count int64, value interface{})
+	SetNXCall         func(key string, value interface{}, expiration time.Duration)
+	GetSetCall        func(key string, value interface{})
+	IncrByCall        func(key string, value int64)
+	MSetCall          func(values map[string]interface{})
 }
 
 func (m *MockPipeline) LRange(key string, start, stop int64) {
@@ -139,6 +167,90 @@ func (m *MockPipeline) Exec() ([]redis.Result, error) {
 	return m.ExecCall()
 }
 
+func (m *MockPipeline) WithContext(ctx context.Context) redis.Pipeline {
+	return m.WithContextCall(ctx)
+}
+
+func (m *MockPipeline) HGet(key string, field string) {
+	m.HGetCall(key, field)
+}
+
+func (m *MockPipeline) HMGet(key string, fields ...string) {
+	m.HMGetCall(key, fields...)
+}
+
+func (m *MockPipeline) HMSet(key string, values map[string]interface{}) {
+	m.HMSetCall(key, values)
+}
+
+func (m *MockPipeline) HDel(key string, fields ...string) {
+	m.HDelCall(key, fields...)
+}
+
+func (m *MockPipeline) HExists(key string, field string) {
+	m.HExistsCall(key, field)
+}
+
+func (m *MockPipeline) HKeys(key string) {
+	m.HKeysCall(key)
+}
+
+func (m *MockPipeline) ZAdd(key string, members ...redis.Z) {
+	m.ZAddCall(key, members...)
+}
+
+func (m *MockPipeline) ZRange(key string, start, stop int64) {
+	m.ZRangeCall(key, start, stop)
+}
+
+func (m *MockPipeline) ZRangeByScore(key string, min, max string, offset, count int64) {
+	m.ZRangeByScoreCall(key, min, max, offset, count)
+}
+
+func (m *MockPipeline) ZRem(key string, members ...interface{}) {
+	m.ZRemCall(key, members...)
+}
+
+func (m *MockPipeline) ZScore(key string, member string) {
+	m.ZScoreCall(key, member)
+}
+
+func (m *MockPipeline) LPop(key string) {
+	m.LPopCall(key)
+}
+
+func (m *MockPipeline) RPop(key string) {
+	m.RPopCall(key)
+}
+
+func (m *MockPipeline) BLPop(timeout time.Duration, keys ...string) {
+	m.BLPopCall(timeout, keys...)
+}
+
+func (m *MockPipeline) LPush(key string, values ...interface{}) {
+	m.LPushCall(key, values...)
+}
+
+func (m *MockPipeline) LRem(key string, count int64, value interface{}) {
+	m.LRemCall(key, count, value)
+}
+
+func (m *MockPipeline) SetNX(key string, value interface{}, expiration time.Duration) {
+	m.SetNXCall(key, value, expiration)
+}
+
+func (m *MockPipeline) GetSet(key string, value interface{}) {
+	m.GetSetCall(key, value)
+}
+
+func (m *MockPipeline) IncrBy(key string, value int64) {
+	m.IncrByCall(key, value)
+}
+
+func (m *MockPipeline) MSet(values map[string]interface{}) {
+	m.MSetCall(values)
+}
+
 // MockClient mocks for testing purposes
 type MockClient struct {
 	ClusterModeCall            func() bool
@@ -171,7 +283,30 @@ type MockClient struct {
 	HSetCall                   func(key string, hashKey string, value interface{}) redis.Result
 	TypeCall                   func(key string) redis.Result
 	PipelineCall               func() redis.Pipeline
+	TxPipelineCall             func() redis.Pipeline
+	WatchCall                  func(keys []string, fn func(tx redis.Client) error) error
 	ScanCall                   func(cursor uint64, match string, count int64) redis.Result
+	WithContextCall            func(ctx context.Context) redis.Client
+	HGetCall                   func(key string, field string) redis.Result
+	HMGetCall                  func(key string, fields ...string) redis.Result
+	HMSetCall                  func(key string, values map[string]interface{}) redis.Result
+	HDelCall                   func(key string, fields ...string) redis.Result
+	HExistsCall                func(key string, field string) redis.Result
+	HKeysCall                  func(key string) redis.Result
+	ZAddCall                   func(key string, members ...redis.Z) redis.Result
+	ZRangeCall                 func(key string, start, stop int64) redis.Result
+	ZRangeByScoreCall          func(key string, min, max string, offset, count int64) redis.Result
+	ZRemCall                   func(key string, members ...interface{}) redis.Result
+	ZScoreCall                 func(key string, member string) redis.Result
+	LPopCall                   func(key string) redis.Result
+	RPopCall                   func(key string) redis.Result
+	BLPopCall                  func(timeout time.Duration, keys ...string) redis.Result
+	LPushCall                  func(key string, values ...interface{}) redis.Result
+	LRemCall                   func(key string, count int64, value interface{}) redis.Result
+	SetNXCall                  func(key string, value interface{}, expiration time.Duration) redis.Result
+	GetSetCall                 func(key string, value interface{}) redis.Result
+	IncrByCall                 func(key string, value int64) redis.Result
+	MSetCall                   func(values map[string]interface{}) redis.Result
 }
 
 func (m *MockClient) ClusterMode() bool {
@@ -320,7 +455,122 @@ func (m *MockClient) Pipeline() redis.Pipeline {
 	return m.PipelineCall()
 }
 
+// TxPipeline mock
+func (m *MockClient) TxPipeline() redis.Pipeline {
+	return m.TxPipelineCall()
+}
+
+// Watch mock
+func (m *MockClient) Watch(keys []string, fn func(tx redis.Client) error) error {
+	return m.WatchCall(keys, fn)
+}
+
 // Scan mock
 func (m *MockClient) Scan(cursor uint64, match string, count int64) redis.Result {
 	return m.ScanCall(cursor, match, count)
 }
+
+// WithContext mock
+func (m *MockClient) WithContext(ctx context.Context) redis.Client {
+	return m.WithContextCall(ctx)
+}
+
+// HGet mock
+func (m *MockClient) HGet(key string, field string) redis.Result {
+	return m.HGetCall(key, field)
+}
+
+// HMGet mock
+func (m *MockClient) HMGet(key string, fields ...string) redis.Result {
+	return m.HMGetCall(key, fields...)
+}
+
+// HMSet mock
+func (m *MockClient) HMSet(key string, values map[string]interface{}) redis.Result {
+	return m.HMSetCall(key, values)
+}
+
+// HDel mock
+func (m *MockClient) HDel(key string, fields ...string) redis.Result {
+	return m.HDelCall(key, fields...)
+}
+
+// HExists mock
+func (m *MockClient) HExists(key string, field string) redis.Result {
+	return m.HExistsCall(key, field)
+}
+
+// HKeys mock
+func (m *MockClient) HKeys(key string) redis.Result {
+	return m.HKeysCall(key)
+}
+
+// ZAdd mock
+func (m *MockClient) ZAdd(key string, members ...redis.Z) redis.Result {
+	return m.ZAddCall(key, members...)
+}
+
+// ZRange mock
+func (m *MockClient) ZRange(key string, start, stop int64) redis.Result {
+	return m.ZRangeCall(key, start, stop)
+}
+
+// ZRangeByScore mock
+func (m *MockClient) ZRangeByScore(key string, min, max string, offset, count int64) redis.Result {
+	return m.ZRangeByScoreCall(key, min, max, offset, count)
+}
+
+// ZRem mock
+func (m *MockClient) ZRem(key string, members ...interface{}) redis.Result {
+	return m.ZRemCall(key, members...)
+}
+
+// ZScore mock
+func (m *MockClient) ZScore(key string, member string) redis.Result {
+	return m.ZScoreCall(key, member)
+}
+
+// LPop mock
+func (m *MockClient) LPop(key string) redis.Result {
+	return m.LPopCall(key)
+}
+
+// RPop mock
+func (m *MockClient) RPop(key string) redis.Result {
+	return m.RPopCall(key)
+}
+
+// BLPop mock
+func (m *MockClient) BLPop(timeout time.Duration, keys ...string) redis.Result {
+	return m.BLPopCall(timeout, keys...)
+}
+
+// LPush mock
+func (m *MockClient) LPush(key string, values ...interface{}) redis.Result {
+	return m.LPushCall(key, values...)
+}
+
+// LRem mock
+func (m *MockClient) LRem(key string, count int64, value interface{}) redis.Result {
+	return m.LRemCall(key, count, value)
+}
+
+// SetNX mock
+func (m *MockClient) SetNX(key string, value interface{}, expiration time.Duration) redis.Result {
+	return m.SetNXCall(key, value, expiration)
+}
+
+// GetSet mock
+func (m *MockClient) GetSet(key string, value interface{}) redis.Result {
+	return m.GetSetCall(key, value)
+}
+
+// IncrBy mock
+func (m *MockClient) IncrBy(key string, value int64) redis.Result {
+	return m.IncrByCall(key, value)
+}
+
+// MSet mock
+func (m *MockClient) MSet(values map[string]interface{}) redis.Result {
+	return m.MSetCall(values)
+}
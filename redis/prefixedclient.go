@@ -1,6 +1,7 @@
 package redis
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -164,11 +165,20 @@ func (p *PrefixedRedisClient) SCard(key string) (int64, error) {
 	return p.client.SCard(withPrefix(p.prefix, key)).Result()
 }
 
-// Eval implements Eval wrapper for redis
+// Eval implements Eval wrapper for redis. Keys are passed as-is, without the prefix (see EvalMultiPrefixed)
 func (p *PrefixedRedisClient) Eval(script string, keys []string, args ...interface{}) error {
 	return p.client.Eval(script, keys, args...).Err()
 }
 
+// EvalMultiPrefixed runs a script that returns a list. Unlike Eval, the supplied keys are prefixed
+func (p *PrefixedRedisClient) EvalMultiPrefixed(script string, keys []string, args ...interface{}) ([]interface{}, error) {
+	prefixedKeys := make([]string, len(keys))
+	for i, k := range keys {
+		prefixedKeys[i] = withPrefix(p.prefix, k)
+	}
+	return p.client.Eval(script, prefixedKeys, args...).MultiInterface()
+}
+
 // HIncrBy implements HIncrBy wrapper for redis
 func (p *PrefixedRedisClient) HIncrBy(key string, field string, value int64) (int64, error) {
 	return p.client.HIncrBy(withPrefix(p.prefix, key), field, value).Result()
@@ -211,11 +221,149 @@ func (p *PrefixedRedisClient) Scan(cursor uint64, match string, count int64) ([]
 	return toReturn, cursor, nil
 }
 
+// HGet returns the value of a hash field
+func (p *PrefixedRedisClient) HGet(key string, field string) (string, error) {
+	return p.client.HGet(withPrefix(p.prefix, key), field).ResultString()
+}
+
+// HMGet returns the values of the supplied hash fields. Missing fields are returned as nil
+func (p *PrefixedRedisClient) HMGet(key string, fields ...string) ([]interface{}, error) {
+	return p.client.HMGet(withPrefix(p.prefix, key), fields...).MultiInterface()
+}
+
+// HMSet sets multiple hash fields at once
+func (p *PrefixedRedisClient) HMSet(key string, values map[string]interface{}) error {
+	return p.client.HMSet(withPrefix(p.prefix, key), values).Err()
+}
+
+// HDel removes fields from a hash, returning how many were removed
+func (p *PrefixedRedisClient) HDel(key string, fields ...string) (int64, error) {
+	return p.client.HDel(withPrefix(p.prefix, key), fields...).Result()
+}
+
+// HExists returns true if the field is present in the hash
+func (p *PrefixedRedisClient) HExists(key string, field string) (bool, error) {
+	res := p.client.HExists(withPrefix(p.prefix, key), field)
+	return res.Bool(), res.Err()
+}
+
+// HKeys returns all the field names of a hash
+func (p *PrefixedRedisClient) HKeys(key string) ([]string, error) {
+	return p.client.HKeys(withPrefix(p.prefix, key)).Multi()
+}
+
+// ZAdd adds members to a sorted set, returning how many were new
+func (p *PrefixedRedisClient) ZAdd(key string, members ...Z) (int64, error) {
+	return p.client.ZAdd(withPrefix(p.prefix, key), members...).Result()
+}
+
+// ZRange returns the members of a sorted set within the supplied rank range
+func (p *PrefixedRedisClient) ZRange(key string, start, stop int64) ([]string, error) {
+	return p.client.ZRange(withPrefix(p.prefix, key), start, stop).Multi()
+}
+
+// ZRangeByScore returns the members of a sorted set with a score between min and max. A count of 0 means no limit
+func (p *PrefixedRedisClient) ZRangeByScore(key string, min, max string, offset, count int64) ([]string, error) {
+	return p.client.ZRangeByScore(withPrefix(p.prefix, key), min, max, offset, count).Multi()
+}
+
+// ZRem removes members from a sorted set
+func (p *PrefixedRedisClient) ZRem(key string, members ...interface{}) (int64, error) {
+	return p.client.ZRem(withPrefix(p.prefix, key), members...).Result()
+}
+
+// ZScore returns the score of a sorted set member
+func (p *PrefixedRedisClient) ZScore(key string, member string) (float64, error) {
+	res := p.client.ZScore(withPrefix(p.prefix, key), member)
+	return res.Float(), res.Err()
+}
+
+// LPop removes and returns the first element of a list
+func (p *PrefixedRedisClient) LPop(key string) (string, error) {
+	return p.client.LPop(withPrefix(p.prefix, key)).ResultString()
+}
+
+// RPop removes and returns the last element of a list
+func (p *PrefixedRedisClient) RPop(key string) (string, error) {
+	return p.client.RPop(withPrefix(p.prefix, key)).ResultString()
+}
+
+// BLPop blocks until an element can be popped from one of the lists, returning the (unprefixed) key and the element
+func (p *PrefixedRedisClient) BLPop(timeout time.Duration, keys ...string) ([]string, error) {
+	prefixedKeys := make([]string, len(keys))
+	for i, k := range keys {
+		prefixedKeys[i] = withPrefix(p.prefix, k)
+	}
+	res, err := p.client.BLPop(timeout, prefixedKeys...).Multi()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res) > 0 {
+		res[0] = withoutPrefix(p.prefix, res[0])
+	}
+	return res, nil
+}
+
+// LPush insert all the specified values at the head of the list stored at key
+func (p *PrefixedRedisClient) LPush(key string, values ...interface{}) (int64, error) {
+	return p.client.LPush(withPrefix(p.prefix, key), values...).Result()
+}
+
+// LRem removes the first count occurrences of value from a list
+func (p *PrefixedRedisClient) LRem(key string, count int64, value interface{}) (int64, error) {
+	return p.client.LRem(withPrefix(p.prefix, key), count, value).Result()
+}
+
+// SetNX sets a key only if it doesn't exist, returning true if it was set
+func (p *PrefixedRedisClient) SetNX(key string, value interface{}, expiration time.Duration) (bool, error) {
+	res := p.client.SetNX(withPrefix(p.prefix, key), value, expiration)
+	return res.Bool(), res.Err()
+}
+
+// GetSet sets a key and returns its previous value
+func (p *PrefixedRedisClient) GetSet(key string, value interface{}) (string, error) {
+	return p.client.GetSet(withPrefix(p.prefix, key), value).ResultString()
+}
+
+// IncrBy increments a key by the supplied value
+func (p *PrefixedRedisClient) IncrBy(key string, value int64) (int64, error) {
+	return p.client.IncrBy(withPrefix(p.prefix, key), value).Result()
+}
+
+// MSet sets multiple keys at once
+func (p *PrefixedRedisClient) MSet(values map[string]interface{}) error {
+	return p.client.MSet(withPrefixedKeys(p.prefix, values)).Err()
+}
+
+// WithContext returns a copy of the client that uses the supplied context for every command
+func (p *PrefixedRedisClient) WithContext(ctx context.Context) *PrefixedRedisClient {
+	return &PrefixedRedisClient{client: p.client.WithContext(ctx), prefix: p.prefix}
+}
+
 // Pipeline wrapper
 func (p *PrefixedRedisClient) Pipeline() Pipeline {
 	return &PrefixedPipeline{wrapped: p.client.Pipeline(), prefix: p.prefix}
 }
 
+// TxPipeline wrapper. Queued operations are applied atomically on execution
+func (p *PrefixedRedisClient) TxPipeline() Pipeline {
+	return &PrefixedPipeline{wrapped: p.client.TxPipeline(), prefix: p.prefix}
+}
+
+// Watch runs fn with a prefixed client on which the supplied keys are being watched. Operations queued on
+// tx.TxPipeline() are only applied if none of those keys changed in the meantime, otherwise TxFailedErr is returned
+func (p *PrefixedRedisClient) Watch(keys []string, fn func(tx *PrefixedRedisClient) error) error {
+	prefixedKeys := make([]string, len(keys))
+	for i, k := range keys {
+		prefixedKeys[i] = withPrefix(p.prefix, k)
+	}
+
+	return p.client.Watch(prefixedKeys, func(tx Client) error {
+		return fn(&PrefixedRedisClient{client: tx, prefix: p.prefix})
+	})
+}
+
 // NewPrefixedRedisClient returns a new Prefixed Redis Client
 func NewPrefixedRedisClient(redisClient Client, prefix string) (*PrefixedRedisClient, error) {
 	return &PrefixedRedisClient{
@@ -294,11 +442,120 @@ func (p *PrefixedPipeline) Del(keys ...string) {
 	p.wrapped.Del(prefixedKeys...)
 }
 
+// HGet schedules an HGet operation on this pipeline
+func (p *PrefixedPipeline) HGet(key string, field string) {
+	p.wrapped.HGet(withPrefix(p.prefix, key), field)
+}
+
+// HMGet schedules an HMGet operation on this pipeline
+func (p *PrefixedPipeline) HMGet(key string, fields ...string) {
+	p.wrapped.HMGet(withPrefix(p.prefix, key), fields...)
+}
+
+// HMSet schedules an HMSet operation on this pipeline
+func (p *PrefixedPipeline) HMSet(key string, values map[string]interface{}) {
+	p.wrapped.HMSet(withPrefix(p.prefix, key), values)
+}
+
+// HDel schedules an HDel operation on this pipeline
+func (p *PrefixedPipeline) HDel(key string, fields ...string) {
+	p.wrapped.HDel(withPrefix(p.prefix, key), fields...)
+}
+
+// HExists schedules an HExists operation on this pipeline
+func (p *PrefixedPipeline) HExists(key string, field string) {
+	p.wrapped.HExists(withPrefix(p.prefix, key), field)
+}
+
+// HKeys schedules an HKeys operation on this pipeline
+func (p *PrefixedPipeline) HKeys(key string) {
+	p.wrapped.HKeys(withPrefix(p.prefix, key))
+}
+
+// ZAdd schedules a ZAdd operation on this pipeline
+func (p *PrefixedPipeline) ZAdd(key string, members ...Z) {
+	p.wrapped.ZAdd(withPrefix(p.prefix, key), members...)
+}
+
+// ZRange schedules a ZRange operation on this pipeline
+func (p *PrefixedPipeline) ZRange(key string, start, stop int64) {
+	p.wrapped.ZRange(withPrefix(p.prefix, key), start, stop)
+}
+
+// ZRangeByScore schedules a ZRangeByScore operation on this pipeline
+func (p *PrefixedPipeline) ZRangeByScore(key string, min, max string, offset, count int64) {
+	p.wrapped.ZRangeByScore(withPrefix(p.prefix, key), min, max, offset, count)
+}
+
+// ZRem schedules a ZRem operation on this pipeline
+func (p *PrefixedPipeline) ZRem(key string, members ...interface{}) {
+	p.wrapped.ZRem(withPrefix(p.prefix, key), members...)
+}
+
+// ZScore schedules a ZScore operation on this pipeline
+func (p *PrefixedPipeline) ZScore(key string, member string) {
+	p.wrapped.ZScore(withPrefix(p.prefix, key), member)
+}
+
+// LPop schedules an LPop operation on this pipeline
+func (p *PrefixedPipeline) LPop(key string) {
+	p.wrapped.LPop(withPrefix(p.prefix, key))
+}
+
+// RPop schedules an RPop operation on this pipeline
+func (p *PrefixedPipeline) RPop(key string) {
+	p.wrapped.RPop(withPrefix(p.prefix, key))
+}
+
+// BLPop schedules a BLPop operation on this pipeline
+func (p *PrefixedPipeline) BLPop(timeout time.Duration, keys ...string) {
+	prefixedKeys := make([]string, len(keys))
+	for i, k := range keys {
+		prefixedKeys[i] = withPrefix(p.prefix, k)
+	}
+	p.wrapped.BLPop(timeout, prefixedKeys...)
+}
+
+// LPush schedules an LPush operation on this pipeline
+func (p *PrefixedPipeline) LPush(key string, values ...interface{}) {
+	p.wrapped.LPush(withPrefix(p.prefix, key), values...)
+}
+
+// LRem schedules an LRem operation on this pipeline
+func (p *PrefixedPipeline) LRem(key string, count int64, value interface{}) {
+	p.wrapped.LRem(withPrefix(p.prefix, key), count, value)
+}
+
+// SetNX schedules a SetNX operation on this pipeline
+func (p *PrefixedPipeline) SetNX(key string, value interface{}, expiration time.Duration) {
+	p.wrapped.SetNX(withPrefix(p.prefix, key), value, expiration)
+}
+
+// GetSet schedules a GetSet operation on this pipeline
+func (p *PrefixedPipeline) GetSet(key string, value interface{}) {
+	p.wrapped.GetSet(withPrefix(p.prefix, key), value)
+}
+
+// IncrBy schedules an IncrBy operation on this pipeline
+func (p *PrefixedPipeline) IncrBy(key string, value int64) {
+	p.wrapped.IncrBy(withPrefix(p.prefix, key), value)
+}
+
+// MSet schedules an MSet operation on this pipeline
+func (p *PrefixedPipeline) MSet(values map[string]interface{}) {
+	p.wrapped.MSet(withPrefixedKeys(p.prefix, values))
+}
+
 // Exec executes the pipeline
 func (p *PrefixedPipeline) Exec() ([]Result, error) {
 	return p.wrapped.Exec()
 }
 
+// WithContext returns a copy of the pipeline that uses the supplied context
+func (p *PrefixedPipeline) WithContext(ctx context.Context) Pipeline {
+	return &PrefixedPipeline{wrapped: p.wrapped.WithContext(ctx), prefix: p.prefix}
+}
+
 // withPrefix adds a prefix to the key if the prefix supplied has a length greater than 0
 func withPrefix(prefix string, key string) string {
 	if len(prefix) > 0 {
@@ -314,3 +571,12 @@ func withoutPrefix(prefix string, key string) string {
 	}
 	return key
 }
+
+// withPrefixedKeys returns a copy of the supplied map with all its keys prefixed
+func withPrefixedKeys(prefix string, values map[string]interface{}) map[string]interface{} {
+	prefixed := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		prefixed[withPrefix(prefix, key)] = value
+	}
+	return prefixed
+}
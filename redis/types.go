@@ -9,6 +9,12 @@ import (
 	wredis "github.com/redis/go-redis/v9"
 )
 
+// Z represents a sorted set member along with its score
+type Z struct {
+	Score  float64
+	Member interface{}
+}
+
 // UniversalOptions type used for redis package
 // TODO(mredolatti): In order to avoid breaking the API, the struct now contains all original fields
 // of go-redis' UniversalOptions struct, with our custom ones.
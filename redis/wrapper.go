@@ -2,6 +2,7 @@ package redis
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -12,12 +13,19 @@ import (
 // Nil represents the redis nil value
 const Nil = redis.Nil
 
+// TxFailedErr is returned when a transaction is aborted because a watched key was modified
+const TxFailedErr = redis.TxFailedErr
+
+// ErrNestedWatch is returned when calling Watch on the client supplied to a transaction callback
+var ErrNestedWatch = errors.New("watch cannot be called within a transaction")
+
 // Result generic interface
 type Result interface {
 	Int() int64
 	String() string
 	Bool() bool
 	Duration() time.Duration
+	Float() float64
 	Result() (int64, error)
 	ResultString() (string, error)
 	Multi() ([]string, error)
@@ -32,6 +40,7 @@ type ResultImpl struct {
 	valueString     string
 	valueBool       bool
 	valueDuration   time.Duration
+	valueFloat      float64
 	err             error
 	multi           []string
 	multiInterface  []interface{}
@@ -58,6 +67,11 @@ func (r *ResultImpl) Duration() time.Duration {
 	return r.valueDuration
 }
 
+// Float implementation
+func (r *ResultImpl) Float() float64 {
+	return r.valueFloat
+}
+
 // Err implementation
 func (r *ResultImpl) Err() error {
 	return r.err
@@ -102,77 +116,211 @@ type Pipeline interface {
 	SRem(key string, members ...interface{})
 	SMembers(key string)
 	Del(keys ...string)
+	HGet(key string, field string)
+	HMGet(key string, fields ...string)
+	HMSet(key string, values map[string]interface{})
+	HDel(key string, fields ...string)
+	HExists(key string, field string)
+	HKeys(key string)
+	ZAdd(key string, members ...Z)
+	ZRange(key string, start, stop int64)
+	ZRangeByScore(key string, min, max string, offset, count int64)
+	ZRem(key string, members ...interface{})
+	ZScore(key string, member string)
+	LPop(key string)
+	RPop(key string)
+	BLPop(timeout time.Duration, keys ...string)
+	LPush(key string, values ...interface{})
+	LRem(key string, count int64, value interface{})
+	SetNX(key string, value interface{}, expiration time.Duration)
+	GetSet(key string, value interface{})
+	IncrBy(key string, value int64)
+	MSet(values map[string]interface{})
 	Exec() ([]Result, error)
+	WithContext(ctx context.Context) Pipeline
 }
 
 // PipelineImpl Wrapper
 type PipelineImpl struct {
 	wrapped redis.Pipeliner
+	ctx     context.Context
+}
+
+// WithContext returns a copy of the pipeline that uses the supplied context for every operation & the execution
+func (p *PipelineImpl) WithContext(ctx context.Context) Pipeline {
+	return &PipelineImpl{wrapped: p.wrapped, ctx: ctx}
+}
+
+func (p *PipelineImpl) requestContext() context.Context {
+	if p.ctx != nil {
+		return p.ctx
+	}
+	return context.TODO()
 }
 
 // LRange schedules an lrange operation on this pipeline
 func (p *PipelineImpl) LRange(key string, start, stop int64) {
-	p.wrapped.LRange(context.TODO(), key, start, stop)
+	p.wrapped.LRange(p.requestContext(), key, start, stop)
 }
 
 // LTrim schedules an ltrim operation on this pipeline
 func (p *PipelineImpl) LTrim(key string, start, stop int64) {
-	p.wrapped.LTrim(context.TODO(), key, start, stop)
+	p.wrapped.LTrim(p.requestContext(), key, start, stop)
 }
 
 // LLen schedules an llen operation on this pipeline
 func (p *PipelineImpl) LLen(key string) {
-	p.wrapped.LLen(context.TODO(), key)
+	p.wrapped.LLen(p.requestContext(), key)
 }
 
 // HIncrBy schedules an hincrby operation on this pipeline
 func (p *PipelineImpl) HIncrBy(key string, field string, value int64) {
-	p.wrapped.HIncrBy(context.TODO(), key, field, value)
+	p.wrapped.HIncrBy(p.requestContext(), key, field, value)
 }
 
 // HLen schedules an HLen operation on this pipeline
 func (p *PipelineImpl) HLen(key string) {
-	p.wrapped.HLen(context.TODO(), key)
+	p.wrapped.HLen(p.requestContext(), key)
 }
 
 // Set schedules a Set operation on this pipeline
 func (p *PipelineImpl) Set(key string, value interface{}, expiration time.Duration) {
-	p.wrapped.Set(context.TODO(), key, value, expiration)
+	p.wrapped.Set(p.requestContext(), key, value, expiration)
 }
 
 // Incr schedules an Incr operation on this pipeline
 func (p *PipelineImpl) Incr(key string) {
-	p.wrapped.Incr(context.TODO(), key)
+	p.wrapped.Incr(p.requestContext(), key)
 }
 
 // Decr schedules a Decr operation on this pipeline
 func (p *PipelineImpl) Decr(key string) {
-	p.wrapped.Decr(context.TODO(), key)
+	p.wrapped.Decr(p.requestContext(), key)
 }
 
 // SAdd schedules a SAdd operation on this pipeline
 func (p *PipelineImpl) SAdd(key string, members ...interface{}) {
-	p.wrapped.SAdd(context.TODO(), key, members...)
+	p.wrapped.SAdd(p.requestContext(), key, members...)
 }
 
 // SRem schedules a SRem operation on this pipeline
 func (p *PipelineImpl) SRem(key string, members ...interface{}) {
-	p.wrapped.SRem(context.TODO(), key, members...)
+	p.wrapped.SRem(p.requestContext(), key, members...)
 }
 
 // SMembers schedules a SMembers operation on this pipeline
 func (p *PipelineImpl) SMembers(key string) {
-	p.wrapped.SMembers(context.TODO(), key)
+	p.wrapped.SMembers(p.requestContext(), key)
 }
 
 // Del schedules a Del operation on this pipeline
 func (p *PipelineImpl) Del(keys ...string) {
-	p.wrapped.Del(context.TODO(), keys...)
+	p.wrapped.Del(p.requestContext(), keys...)
+}
+
+// HGet schedules an HGet operation on this pipeline
+func (p *PipelineImpl) HGet(key string, field string) {
+	p.wrapped.HGet(p.requestContext(), key, field)
+}
+
+// HMGet schedules an HMGet operation on this pipeline
+func (p *PipelineImpl) HMGet(key string, fields ...string) {
+	p.wrapped.HMGet(p.requestContext(), key, fields...)
+}
+
+// HMSet schedules an HMSet operation on this pipeline
+func (p *PipelineImpl) HMSet(key string, values map[string]interface{}) {
+	p.wrapped.HMSet(p.requestContext(), key, values)
+}
+
+// HDel schedules an HDel operation on this pipeline
+func (p *PipelineImpl) HDel(key string, fields ...string) {
+	p.wrapped.HDel(p.requestContext(), key, fields...)
+}
+
+// HExists schedules an HExists operation on this pipeline
+func (p *PipelineImpl) HExists(key string, field string) {
+	p.wrapped.HExists(p.requestContext(), key, field)
+}
+
+// HKeys schedules an HKeys operation on this pipeline
+func (p *PipelineImpl) HKeys(key string) {
+	p.wrapped.HKeys(p.requestContext(), key)
+}
+
+// ZAdd schedules a ZAdd operation on this pipeline
+func (p *PipelineImpl) ZAdd(key string, members ...Z) {
+	p.wrapped.ZAdd(p.requestContext(), key, toRedisZ(members)...)
+}
+
+// ZRange schedules a ZRange operation on this pipeline
+func (p *PipelineImpl) ZRange(key string, start, stop int64) {
+	p.wrapped.ZRange(p.requestContext(), key, start, stop)
+}
+
+// ZRangeByScore schedules a ZRangeByScore operation on this pipeline
+func (p *PipelineImpl) ZRangeByScore(key string, min, max string, offset, count int64) {
+	p.wrapped.ZRangeByScore(p.requestContext(), key, toRedisRangeBy(min, max, offset, count))
+}
+
+// ZRem schedules a ZRem operation on this pipeline
+func (p *PipelineImpl) ZRem(key string, members ...interface{}) {
+	p.wrapped.ZRem(p.requestContext(), key, members...)
+}
+
+// ZScore schedules a ZScore operation on this pipeline
+func (p *PipelineImpl) ZScore(key string, member string) {
+	p.wrapped.ZScore(p.requestContext(), key, member)
+}
+
+// LPop schedules an LPop operation on this pipeline
+func (p *PipelineImpl) LPop(key string) {
+	p.wrapped.LPop(p.requestContext(), key)
+}
+
+// RPop schedules an RPop operation on this pipeline
+func (p *PipelineImpl) RPop(key string) {
+	p.wrapped.RPop(p.requestContext(), key)
+}
+
+// BLPop schedules a BLPop operation on this pipeline
+func (p *PipelineImpl) BLPop(timeout time.Duration, keys ...string) {
+	p.wrapped.BLPop(p.requestContext(), timeout, keys...)
+}
+
+// LPush schedules an LPush operation on this pipeline
+func (p *PipelineImpl) LPush(key string, values ...interface{}) {
+	p.wrapped.LPush(p.requestContext(), key, values...)
+}
+
+// LRem schedules an LRem operation on this pipeline
+func (p *PipelineImpl) LRem(key string, count int64, value interface{}) {
+	p.wrapped.LRem(p.requestContext(), key, count, value)
+}
+
+// SetNX schedules a SetNX operation on this pipeline
+func (p *PipelineImpl) SetNX(key string, value interface{}, expiration time.Duration) {
+	p.wrapped.SetNX(p.requestContext(), key, value, expiration)
+}
+
+// GetSet schedules a GetSet operation on this pipeline
+func (p *PipelineImpl) GetSet(key string, value interface{}) {
+	p.wrapped.GetSet(p.requestContext(), key, value)
+}
+
+// IncrBy schedules an IncrBy operation on this pipeline
+func (p *PipelineImpl) IncrBy(key string, value int64) {
+	p.wrapped.IncrBy(p.requestContext(), key, value)
+}
+
+// MSet schedules an MSet operation on this pipeline
+func (p *PipelineImpl) MSet(values map[string]interface{}) {
+	p.wrapped.MSet(p.requestContext(), values)
 }
 
 // Exec executes the pipeline
 func (p *PipelineImpl) Exec() ([]Result, error) {
-	res, err := p.wrapped.Exec(context.TODO())
+	res, err := p.wrapped.Exec(p.requestContext())
 	if err != nil {
 		return nil, err
 	}
@@ -219,13 +367,50 @@ type Client interface {
 	HGetAll(key string) Result
 	Type(key string) Result
 	Pipeline() Pipeline
+	TxPipeline() Pipeline
+	Watch(keys []string, fn func(tx Client) error) error
 	Scan(cursor uint64, match string, count int64) Result
+	HGet(key string, field string) Result
+	HMGet(key string, fields ...string) Result
+	HMSet(key string, values map[string]interface{}) Result
+	HDel(key string, fields ...string) Result
+	HExists(key string, field string) Result
+	HKeys(key string) Result
+	ZAdd(key string, members ...Z) Result
+	ZRange(key string, start, stop int64) Result
+	ZRangeByScore(key string, min, max string, offset, count int64) Result
+	ZRem(key string, members ...interface{}) Result
+	ZScore(key string, member string) Result
+	LPop(key string) Result
+	RPop(key string) Result
+	BLPop(timeout time.Duration, keys ...string) Result
+	LPush(key string, values ...interface{}) Result
+	LRem(key string, count int64, value interface{}) Result
+	SetNX(key string, value interface{}, expiration time.Duration) Result
+	GetSet(key string, value interface{}) Result
+	IncrBy(key string, value int64) Result
+	MSet(values map[string]interface{}) Result
+	WithContext(ctx context.Context) Client
 }
 
 // ClientImpl wrapps redis client
 type ClientImpl struct {
-	wrapped     redis.UniversalClient
+	wrapped     redis.Cmdable
 	clusterMode bool
+	ctx         context.Context
+}
+
+// WithContext returns a copy of the client that uses the supplied context for every command, so that
+// deadlines, cancellation & tracing reach redis. Pipelines created from it inherit the context
+func (c *ClientImpl) WithContext(ctx context.Context) Client {
+	return &ClientImpl{wrapped: c.wrapped, clusterMode: c.clusterMode, ctx: ctx}
+}
+
+func (c *ClientImpl) requestContext() context.Context {
+	if c.ctx != nil {
+		return c.ctx
+	}
+	return context.TODO()
 }
 
 // ClusterMode returns true if the client is running in cluster mode
@@ -235,181 +420,326 @@ func (c *ClientImpl) ClusterMode() bool {
 
 // ClusterSlotForKey returns the slot for the supplied key
 func (c *ClientImpl) ClusterSlotForKey(key string) Result {
-	res := c.wrapped.ClusterKeySlot(context.TODO(), key)
+	res := c.wrapped.ClusterKeySlot(c.requestContext(), key)
 	return wrapResult(res)
 }
 
 // ClusterCountKeysInSlot returns the number of keys in slot
 func (c *ClientImpl) ClusterCountKeysInSlot(slot int) Result {
-	res := c.wrapped.ClusterCountKeysInSlot(context.TODO(), slot)
+	res := c.wrapped.ClusterCountKeysInSlot(c.requestContext(), slot)
 	return wrapResult(res)
 }
 
 // ClusterKeysInSlot returns all the keys in the supplied slot
 func (c *ClientImpl) ClusterKeysInSlot(slot int, count int) Result {
-	res := c.wrapped.ClusterGetKeysInSlot(context.TODO(), slot, count)
+	res := c.wrapped.ClusterGetKeysInSlot(c.requestContext(), slot, count)
 	return wrapResult(res)
 }
 
 // Del implements Del wrapper for redis
 func (c *ClientImpl) Del(keys ...string) Result {
-	res := c.wrapped.Del(context.TODO(), keys...)
+	res := c.wrapped.Del(c.requestContext(), keys...)
 	return wrapResult(res)
 }
 
 // Exists implements Exists wrapper for redis
 func (c *ClientImpl) Exists(keys ...string) Result {
-	res := c.wrapped.Exists(context.TODO(), keys...)
+	res := c.wrapped.Exists(c.requestContext(), keys...)
 	return wrapResult(res)
 }
 
 // Get implements Get wrapper for redis
 func (c *ClientImpl) Get(key string) Result {
-	res := c.wrapped.Get(context.TODO(), key)
+	res := c.wrapped.Get(c.requestContext(), key)
 	return wrapResult(res)
 }
 
 // Set implements Set wrapper for redis
 func (c *ClientImpl) Set(key string, value interface{}, expiration time.Duration) Result {
-	res := c.wrapped.Set(context.TODO(), key, value, expiration)
+	res := c.wrapped.Set(c.requestContext(), key, value, expiration)
 	return wrapResult(res)
 }
 
 // Ping implements Ping wrapper for redis
 func (c *ClientImpl) Ping() Result {
-	res := c.wrapped.Ping(context.TODO())
+	res := c.wrapped.Ping(c.requestContext())
 	return wrapResult(res)
 }
 
 // Keys implements Keys wrapper for redis
 func (c *ClientImpl) Keys(pattern string) Result {
-	res := c.wrapped.Keys(context.TODO(), pattern)
+	res := c.wrapped.Keys(c.requestContext(), pattern)
 	return wrapResult(res)
 }
 
 // SMembers implements SMembers wrapper for redis
 func (c *ClientImpl) SMembers(key string) Result {
-	res := c.wrapped.SMembers(context.TODO(), key)
+	res := c.wrapped.SMembers(c.requestContext(), key)
 	return wrapResult(res)
 }
 
 // SIsMember implements SIsMember wrapper for redis
 func (c *ClientImpl) SIsMember(key string, member interface{}) Result {
-	res := c.wrapped.SIsMember(context.TODO(), key, member)
+	res := c.wrapped.SIsMember(c.requestContext(), key, member)
 	return wrapResult(res)
 }
 
 // SAdd implements SAdd wrapper for redis
 func (c *ClientImpl) SAdd(key string, members ...interface{}) Result {
-	res := c.wrapped.SAdd(context.TODO(), key, members...)
+	res := c.wrapped.SAdd(c.requestContext(), key, members...)
 	return wrapResult(res)
 }
 
 // SRem implements SRem wrapper for redis
 func (c *ClientImpl) SRem(key string, members ...interface{}) Result {
-	res := c.wrapped.SRem(context.TODO(), key, members...)
+	res := c.wrapped.SRem(c.requestContext(), key, members...)
 	return wrapResult(res)
 }
 
 // Incr implements Incr wrapper for redis
 func (c *ClientImpl) Incr(key string) Result {
-	res := c.wrapped.Incr(context.TODO(), key)
+	res := c.wrapped.Incr(c.requestContext(), key)
 	return wrapResult(res)
 }
 
 // Decr implements Decr wrapper for redis
 func (c *ClientImpl) Decr(key string) Result {
-	res := c.wrapped.Decr(context.TODO(), key)
+	res := c.wrapped.Decr(c.requestContext(), key)
 	return wrapResult(res)
 }
 
 // RPush implements RPush wrapper for redis
 func (c *ClientImpl) RPush(key string, values ...interface{}) Result {
-	res := c.wrapped.RPush(context.TODO(), key, values...)
+	res := c.wrapped.RPush(c.requestContext(), key, values...)
 	return wrapResult(res)
 }
 
 // LRange implements LRange wrapper for redis
 func (c *ClientImpl) LRange(key string, start, stop int64) Result {
-	res := c.wrapped.LRange(context.TODO(), key, start, stop)
+	res := c.wrapped.LRange(c.requestContext(), key, start, stop)
 	return wrapResult(res)
 }
 
 // LTrim implements LTrim wrapper for redis
 func (c *ClientImpl) LTrim(key string, start, stop int64) Result {
-	res := c.wrapped.LTrim(context.TODO(), key, start, stop)
+	res := c.wrapped.LTrim(c.requestContext(), key, start, stop)
 	return wrapResult(res)
 }
 
 // LLen implements LLen wrapper for redis
 func (c *ClientImpl) LLen(key string) Result {
-	res := c.wrapped.LLen(context.TODO(), key)
+	res := c.wrapped.LLen(c.requestContext(), key)
 	return wrapResult(res)
 }
 
 // Expire implements Expire wrapper for redis
 func (c *ClientImpl) Expire(key string, value time.Duration) Result {
-	res := c.wrapped.Expire(context.TODO(), key, value)
+	res := c.wrapped.Expire(c.requestContext(), key, value)
 	return wrapResult(res)
 }
 
 // TTL implements TTL wrapper for redis
 func (c *ClientImpl) TTL(key string) Result {
-	res := c.wrapped.TTL(context.TODO(), key)
+	res := c.wrapped.TTL(c.requestContext(), key)
 	return wrapResult(res)
 }
 
 // MGet implements MGet wrapper for redis
 func (c *ClientImpl) MGet(keys []string) Result {
-	res := c.wrapped.MGet(context.TODO(), keys...)
+	res := c.wrapped.MGet(c.requestContext(), keys...)
 	return wrapResult(res)
 }
 
 // SCard implements SCard wrapper for redis
 func (c *ClientImpl) SCard(key string) Result {
-	res := c.wrapped.SCard(context.TODO(), key)
+	res := c.wrapped.SCard(c.requestContext(), key)
 	return wrapResult(res)
 }
 
 // Eval implements Eval wrapper for redis
 func (c *ClientImpl) Eval(script string, keys []string, args ...interface{}) Result {
-	res := c.wrapped.Eval(context.TODO(), script, keys, args...)
+	res := c.wrapped.Eval(c.requestContext(), script, keys, args...)
 	return wrapResult(res)
 }
 
 // HIncrBy implements HIncrBy wrapper for redis
 func (c *ClientImpl) HIncrBy(key string, field string, value int64) Result {
-	res := c.wrapped.HIncrBy(context.TODO(), key, field, value)
+	res := c.wrapped.HIncrBy(c.requestContext(), key, field, value)
 	return wrapResult(res)
 }
 
 // HSet implements HSet wrapper for redis
 func (c *ClientImpl) HSet(key string, hashKey string, value interface{}) Result {
-	res := c.wrapped.HSet(context.TODO(), key, hashKey, value)
+	res := c.wrapped.HSet(c.requestContext(), key, hashKey, value)
 	return wrapResult(res)
 }
 
 // HGetAll implements HGetAll wrapper for redis
 func (c *ClientImpl) HGetAll(key string) Result {
-	res := c.wrapped.HGetAll(context.TODO(), key)
+	res := c.wrapped.HGetAll(c.requestContext(), key)
 	return wrapResult(res)
 }
 
 // Type implements Type wrapper for redis
 func (c *ClientImpl) Type(key string) Result {
-	res := c.wrapped.Type(context.TODO(), key)
+	res := c.wrapped.Type(c.requestContext(), key)
 	return wrapResult(res)
 }
 
 // Pipeline implements Pipeline wrapper for redis
 func (c *ClientImpl) Pipeline() Pipeline {
 	res := c.wrapped.Pipeline()
-	return &PipelineImpl{wrapped: res}
+	return &PipelineImpl{wrapped: res, ctx: c.ctx}
+}
+
+// TxPipeline implements TxPipeline wrapper for redis. Queued operations are wrapped in MULTI/EXEC on execution
+func (c *ClientImpl) TxPipeline() Pipeline {
+	res := c.wrapped.TxPipeline()
+	return &PipelineImpl{wrapped: res, ctx: c.ctx}
+}
+
+// watcher is implemented by the go-redis clients able to start transactions
+type watcher interface {
+	Watch(ctx context.Context, fn func(*redis.Tx) error, keys ...string) error
+}
+
+// Watch implements optimistic locking. fn receives a client bound to a dedicated connection on which keys are
+// being watched. Operations queued on tx.TxPipeline() are only applied if none of those keys was modified by
+// someone else before the pipeline is executed, otherwise TxFailedErr is returned and the caller may retry
+func (c *ClientImpl) Watch(keys []string, fn func(tx Client) error) error {
+	w, ok := c.wrapped.(watcher)
+	if !ok {
+		return ErrNestedWatch
+	}
+
+	return w.Watch(c.requestContext(), func(tx *redis.Tx) error {
+		return fn(&ClientImpl{wrapped: tx, clusterMode: c.clusterMode, ctx: c.ctx})
+	}, keys...)
 }
 
 // Scan implements Scan wrapper for redis
 func (c *ClientImpl) Scan(cursor uint64, match string, count int64) Result {
-	res := c.wrapped.Scan(context.TODO(), cursor, match, count)
+	res := c.wrapped.Scan(c.requestContext(), cursor, match, count)
+	return wrapResult(res)
+}
+
+// HGet implements HGet wrapper for redis
+func (c *ClientImpl) HGet(key string, field string) Result {
+	res := c.wrapped.HGet(c.requestContext(), key, field)
+	return wrapResult(res)
+}
+
+// HMGet implements HMGet wrapper for redis
+func (c *ClientImpl) HMGet(key string, fields ...string) Result {
+	res := c.wrapped.HMGet(c.requestContext(), key, fields...)
+	return wrapResult(res)
+}
+
+// HMSet implements HMSet wrapper for redis
+func (c *ClientImpl) HMSet(key string, values map[string]interface{}) Result {
+	res := c.wrapped.HMSet(c.requestContext(), key, values)
+	return wrapResult(res)
+}
+
+// HDel implements HDel wrapper for redis
+func (c *ClientImpl) HDel(key string, fields ...string) Result {
+	res := c.wrapped.HDel(c.requestContext(), key, fields...)
+	return wrapResult(res)
+}
+
+// HExists implements HExists wrapper for redis
+func (c *ClientImpl) HExists(key string, field string) Result {
+	res := c.wrapped.HExists(c.requestContext(), key, field)
+	return wrapResult(res)
+}
+
+// HKeys implements HKeys wrapper for redis
+func (c *ClientImpl) HKeys(key string) Result {
+	res := c.wrapped.HKeys(c.requestContext(), key)
+	return wrapResult(res)
+}
+
+// ZAdd implements ZAdd wrapper for redis
+func (c *ClientImpl) ZAdd(key string, members ...Z) Result {
+	res := c.wrapped.ZAdd(c.requestContext(), key, toRedisZ(members)...)
+	return wrapResult(res)
+}
+
+// ZRange implements ZRange wrapper for redis
+func (c *ClientImpl) ZRange(key string, start, stop int64) Result {
+	res := c.wrapped.ZRange(c.requestContext(), key, start, stop)
+	return wrapResult(res)
+}
+
+// ZRangeByScore implements ZRangeByScore wrapper for redis
+func (c *ClientImpl) ZRangeByScore(key string, min, max string, offset, count int64) Result {
+	res := c.wrapped.ZRangeByScore(c.requestContext(), key, toRedisRangeBy(min, max, offset, count))
+	return wrapResult(res)
+}
+
+// ZRem implements ZRem wrapper for redis
+func (c *ClientImpl) ZRem(key string, members ...interface{}) Result {
+	res := c.wrapped.ZRem(c.requestContext(), key, members...)
+	return wrapResult(res)
+}
+
+// ZScore implements ZScore wrapper for redis
+func (c *ClientImpl) ZScore(key string, member string) Result {
+	res := c.wrapped.ZScore(c.requestContext(), key, member)
+	return wrapResult(res)
+}
+
+// LPop implements LPop wrapper for redis
+func (c *ClientImpl) LPop(key string) Result {
+	res := c.wrapped.LPop(c.requestContext(), key)
+	return wrapResult(res)
+}
+
+// RPop implements RPop wrapper for redis
+func (c *ClientImpl) RPop(key string) Result {
+	res := c.wrapped.RPop(c.requestContext(), key)
+	return wrapResult(res)
+}
+
+// BLPop implements BLPop wrapper for redis
+func (c *ClientImpl) BLPop(timeout time.Duration, keys ...string) Result {
+	res := c.wrapped.BLPop(c.requestContext(), timeout, keys...)
+	return wrapResult(res)
+}
+
+// LPush implements LPush wrapper for redis
+func (c *ClientImpl) LPush(key string, values ...interface{}) Result {
+	res := c.wrapped.LPush(c.requestContext(), key, values...)
+	return wrapResult(res)
+}
+
+// LRem implements LRem wrapper for redis
+func (c *ClientImpl) LRem(key string, count int64, value interface{}) Result {
+	res := c.wrapped.LRem(c.requestContext(), key, count, value)
+	return wrapResult(res)
+}
+
+// SetNX implements SetNX wrapper for redis
+func (c *ClientImpl) SetNX(key string, value interface{}, expiration time.Duration) Result {
+	res := c.wrapped.SetNX(c.requestContext(), key, value, expiration)
+	return wrapResult(res)
+}
+
+// GetSet implements GetSet wrapper for redis
+func (c *ClientImpl) GetSet(key string, value interface{}) Result {
+	res := c.wrapped.GetSet(c.requestContext(), key, value)
+	return wrapResult(res)
+}
+
+// IncrBy implements IncrBy wrapper for redis
+func (c *ClientImpl) IncrBy(key string, value int64) Result {
+	res := c.wrapped.IncrBy(c.requestContext(), key, value)
+	return wrapResult(res)
+}
+
+// MSet implements MSet wrapper for redis
+func (c *ClientImpl) MSet(values map[string]interface{}) Result {
+	res := c.wrapped.MSet(c.requestContext(), values)
 	return wrapResult(res)
 }
 
@@ -426,6 +756,22 @@ func NewClient(options *UniversalOptions) (Client, error) {
 	}, nil
 }
 
+func toRedisZ(members []Z) []redis.Z {
+	toRet := make([]redis.Z, len(members))
+	for idx := range members {
+		toRet[idx] = redis.Z{Score: members[idx].Score, Member: members[idx].Member}
+	}
+	return toRet
+}
+
+// toRedisRangeBy builds a score range. A count of 0 means no limit, which redis expresses with a negative count
+func toRedisRangeBy(min, max string, offset, count int64) *redis.ZRangeBy {
+	if count == 0 && offset != 0 {
+		count = -1
+	}
+	return &redis.ZRangeBy{Min: min, Max: max, Offset: offset, Count: count}
+}
+
 func wrapResult(result interface{}) Result {
 	if result == nil {
 		return nil
@@ -461,15 +807,27 @@ func wrapResult(result interface{}) Result {
 			valueDuration: v.Val(),
 			err:           v.Err(),
 		}
+	case *redis.FloatCmd:
+		return &ResultImpl{
+			valueFloat: v.Val(),
+			err:        v.Err(),
+		}
 	case *redis.SliceCmd:
 		return &ResultImpl{
 			err:            v.Err(),
 			multiInterface: v.Val(),
 		}
 	case *redis.Cmd:
-		return &ResultImpl{
-			err: v.Err(),
+		toRet := &ResultImpl{err: v.Err()}
+		switch val := v.Val().(type) {
+		case int64:
+			toRet.value = val
+		case string:
+			toRet.valueString = val
+		case []interface{}:
+			toRet.multiInterface = val
 		}
+		return toRet
 	case *redis.MapStringStringCmd:
 		return &ResultImpl{
 			err:             v.Err(),
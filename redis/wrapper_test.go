@@ -1,6 +1,7 @@
 package redis
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -145,3 +146,229 @@ func TestRedisWrapperPipeline(t *testing.T) {
 		t.Error("count should be 2. Is: ", c)
 	}
 }
+
+func TestRedisWrapperHashesSortedSetsAndLists(t *testing.T) {
+	rc := redis.NewUniversalClient(&redis.UniversalOptions{})
+	client := &ClientImpl{wrapped: rc}
+	prefixed, _ := NewPrefixedRedisClient(client, "utest")
+	prefixed.Del("hash", "zset", "list", "nx", "k1", "k2")
+
+	if err := prefixed.HMSet("hash", map[string]interface{}{"f1": "v1", "f2": "v2"}); err != nil {
+		t.Error("there should not be any error. Got: ", err)
+	}
+	if val, _ := prefixed.HGet("hash", "f1"); val != "v1" {
+		t.Error("f1 should be v1. Is: ", val)
+	}
+	if vals, _ := prefixed.HMGet("hash", "f2", "missing"); len(vals) != 2 || vals[0] != "v2" || vals[1] != nil {
+		t.Error("hmget should return v2 & nil. Got: ", vals)
+	}
+	if exists, _ := prefixed.HExists("hash", "f2"); !exists {
+		t.Error("f2 should exist")
+	}
+	if removed, _ := prefixed.HDel("hash", "f2"); removed != 1 {
+		t.Error("1 field should have been removed. Got: ", removed)
+	}
+	if keys, _ := prefixed.HKeys("hash"); len(keys) != 1 || keys[0] != "f1" {
+		t.Error("only f1 should remain. Got: ", keys)
+	}
+
+	if added, _ := prefixed.ZAdd("zset", Z{Score: 3, Member: "c"}, Z{Score: 1, Member: "a"}, Z{Score: 2, Member: "b"}); added != 3 {
+		t.Error("3 members should have been added. Got: ", added)
+	}
+	members, _ := prefixed.ZRange("zset", 0, -1)
+	testhelpers.AssertStringSliceEquals(t, members, []string{"a", "b", "c"}, "members should be sorted by score")
+	members, _ = prefixed.ZRangeByScore("zset", "(1", "+inf", 0, 1)
+	testhelpers.AssertStringSliceEquals(t, members, []string{"b"}, "only b should be returned")
+	if score, _ := prefixed.ZScore("zset", "c"); score != 3 {
+		t.Error("score should be 3. Is: ", score)
+	}
+	if removed, _ := prefixed.ZRem("zset", "a", "missing"); removed != 1 {
+		t.Error("1 member should have been removed. Got: ", removed)
+	}
+
+	prefixed.LPush("list", "b", "a")
+	prefixed.RPush("list", "x", "c", "x")
+	if removed, _ := prefixed.LRem("list", 0, "x"); removed != 2 {
+		t.Error("2 items should have been removed. Got: ", removed)
+	}
+	if item, _ := prefixed.LPop("list"); item != "a" {
+		t.Error("head should be a. Is: ", item)
+	}
+	if item, _ := prefixed.RPop("list"); item != "c" {
+		t.Error("tail should be c. Is: ", item)
+	}
+	popped, _ := prefixed.BLPop(time.Second, "missing", "list")
+	testhelpers.AssertStringSliceEquals(t, popped, []string{"list", "b"}, "blpop should return the unprefixed key & item")
+
+	if set, _ := prefixed.SetNX("nx", "first", time.Hour); !set {
+		t.Error("key should have been set")
+	}
+	if set, _ := prefixed.SetNX("nx", "second", time.Hour); set {
+		t.Error("key should not be overwritten")
+	}
+	if old, _ := prefixed.GetSet("nx", "third"); old != "first" {
+		t.Error("previous value should be first. Is: ", old)
+	}
+
+	prefixed.MSet(map[string]interface{}{"k1": 1, "k2": 2})
+	if val, _ := prefixed.IncrBy("k1", 5); val != 6 {
+		t.Error("k1 should be 6. Is: ", val)
+	}
+	if val, _ := prefixed.Get("k2"); val != "2" {
+		t.Error("k2 should be 2. Is: ", val)
+	}
+
+	pipe := prefixed.Pipeline()
+	pipe.HGet("hash", "f1")
+	pipe.ZScore("zset", "b")
+	pipe.IncrBy("k2", 3)
+	result, err := pipe.Exec()
+	if err != nil || len(result) != 3 {
+		t.Error("there should be 3 results & no error. Got: ", len(result), err)
+	}
+	if result[0].String() != "v1" || result[1].Float() != 2 || result[2].Int() != 5 {
+		t.Error("unexpected pipeline results")
+	}
+
+	prefixed.Del("hash", "zset", "list", "nx", "k1", "k2")
+}
+
+func TestWrapCmdResult(t *testing.T) {
+	cmd := redis.NewCmd(context.Background())
+	cmd.SetVal([]interface{}{"a", "b"})
+	if items, err := wrapResult(cmd).MultiInterface(); err != nil || len(items) != 2 || items[0] != "a" {
+		t.Error("list values should be wrapped. Got: ", items, err)
+	}
+
+	cmd.SetVal(int64(3))
+	if val := wrapResult(cmd).Int(); val != 3 {
+		t.Error("int values should be wrapped. Got: ", val)
+	}
+
+	cmd.SetVal("someValue")
+	if val := wrapResult(cmd).String(); val != "someValue" {
+		t.Error("string values should be wrapped. Got: ", val)
+	}
+
+	floatCmd := redis.NewFloatCmd(context.Background())
+	floatCmd.SetVal(1.5)
+	if val := wrapResult(floatCmd).Float(); val != 1.5 {
+		t.Error("float values should be wrapped. Got: ", val)
+	}
+}
+
+func TestWithContext(t *testing.T) {
+	rc := redis.NewUniversalClient(&redis.UniversalOptions{Addrs: []string{"127.0.0.1:1"}})
+	client := &ClientImpl{wrapped: rc}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	withCtx := client.WithContext(ctx)
+	if client.requestContext() != context.TODO() {
+		t.Error("the original client should keep the default context")
+	}
+
+	if err := withCtx.Get("someKey").Err(); err != context.Canceled {
+		t.Error("a canceled context should abort the command. Got: ", err)
+	}
+
+	pipe := withCtx.Pipeline()
+	pipe.Incr("someCounter")
+	if _, err := pipe.Exec(); err != context.Canceled {
+		t.Error("a canceled context should abort the pipeline. Got: ", err)
+	}
+
+	txPipe := withCtx.TxPipeline()
+	txPipe.Incr("someCounter")
+	if _, err := txPipe.Exec(); err != context.Canceled {
+		t.Error("a canceled context should abort the transaction. Got: ", err)
+	}
+
+	prefixed, _ := NewPrefixedRedisClient(client, "prefix")
+	if err := prefixed.WithContext(ctx).Set("someKey", "someValue", 0); err != context.Canceled {
+		t.Error("a canceled context should reach the prefixed client. Got: ", err)
+	}
+
+	called := false
+	err := prefixed.WithContext(ctx).Watch([]string{"someKey"}, func(tx *PrefixedRedisClient) error {
+		called = true
+		return nil
+	})
+	if err != context.Canceled || called {
+		t.Error("a canceled context should abort the watch before invoking the callback. Got: ", err)
+	}
+}
+
+func TestRedisWrapperTransactions(t *testing.T) {
+	rc := redis.NewUniversalClient(&redis.UniversalOptions{})
+	client := &ClientImpl{wrapped: rc}
+	prefixed, _ := NewPrefixedRedisClient(client, "utest")
+	prefixed.Del("split", "changeNumber")
+	prefixed.Set("changeNumber", 1, 0)
+
+	update := func(tx *PrefixedRedisClient, definition string) error {
+		current, err := tx.Get("changeNumber")
+		if err != nil {
+			return err
+		}
+
+		pipe := tx.TxPipeline()
+		pipe.Set("split", definition, 0)
+		pipe.IncrBy("changeNumber", 1)
+		_, err = pipe.Exec()
+		if current != "1" {
+			t.Error("change number should be read within the transaction. Got: ", current)
+		}
+		return err
+	}
+
+	err := prefixed.Watch([]string{"split", "changeNumber"}, func(tx *PrefixedRedisClient) error {
+		return update(tx, "first")
+	})
+	if err != nil {
+		t.Error("there should not be any error. Got: ", err)
+	}
+	if val, _ := prefixed.Get("changeNumber"); val != "2" {
+		t.Error("change number should be 2. Is: ", val)
+	}
+
+	prefixed.Set("changeNumber", 1, 0)
+	err = prefixed.Watch([]string{"split", "changeNumber"}, func(tx *PrefixedRedisClient) error {
+		prefixed.Set("split", "concurrent", 0) // modified by someone else after being watched
+		return update(tx, "second")
+	})
+	if err != TxFailedErr {
+		t.Error("the transaction should fail. Got: ", err)
+	}
+	if val, _ := prefixed.Get("split"); val != "concurrent" {
+		t.Error("the concurrent write should be kept. Got: ", val)
+	}
+
+	err = prefixed.Watch([]string{"split"}, func(tx *PrefixedRedisClient) error {
+		return tx.Watch([]string{"changeNumber"}, func(*PrefixedRedisClient) error { return nil })
+	})
+	if err != ErrNestedWatch {
+		t.Error("nested watches should be rejected. Got: ", err)
+	}
+
+	pipe := prefixed.TxPipeline()
+	pipe.Incr("changeNumber")
+	pipe.Incr("changeNumber")
+	if result, err := pipe.Exec(); err != nil || len(result) != 2 || result[1].Int() != 3 {
+		t.Error("both increments should be applied. Got: ", result, err)
+	}
+
+	prefixed.Del("split", "changeNumber")
+}
+
+func TestToRedisRangeBy(t *testing.T) {
+	if opt := toRedisRangeBy("1", "2", 0, 0); opt.Offset != 0 || opt.Count != 0 {
+		t.Error("no limit should be sent without an offset. Got: ", opt)
+	}
+	if opt := toRedisRangeBy("1", "2", 3, 0); opt.Offset != 3 || opt.Count != -1 {
+		t.Error("a zero count with an offset should mean no limit. Got: ", opt)
+	}
+	if opt := toRedisRangeBy("-inf", "+inf", 1, 5); opt.Min != "-inf" || opt.Max != "+inf" || opt.Count != 5 {
+		t.Error("supplied values should be kept. Got: ", opt)
+	}
+}
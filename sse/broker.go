@@ -0,0 +1,409 @@
+package sse
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/splitio/go-toolkit/v5/logging"
+)
+
+const (
+	defaultChannelParam   = "channel"
+	defaultSubscriberSize = 100
+	lastEventIDHeader     = "Last-Event-ID"
+	keepAliveComment      = ":keepalive\n\n"
+)
+
+// ErrBrokerClosed is the error to return when publishing on a broker that has been closed
+var ErrBrokerClosed = errors.New("sse broker closed")
+
+// BrokerOptions contains the parameters used to customize a Broker
+type BrokerOptions struct {
+	// KeepAlive is the interval at which keepalive comments are sent to every subscriber. Zero disables them
+	KeepAlive time.Duration
+	// HistorySize is the number of events retained per channel to be replayed using the Last-Event-ID header
+	HistorySize int
+	// ChannelParam is the query parameter holding the comma-separated list of channels to subscribe to. Defaults to "channel"
+	ChannelParam string
+	// SubscriberBufferSize is the number of events that can be queued for a subscriber before it's considered
+	// too slow and gets disconnected. Defaults to 100
+	SubscriberBufferSize int
+}
+
+type historyEntry struct {
+	seq     uint64
+	event   RawEvent
+	payload []byte
+}
+
+type subscriber struct {
+	channels []string
+	out      chan []byte
+	dropped  chan struct{}
+}
+
+// Broker is an http.Handler that streams published events to subscribers grouped by channel
+type Broker struct {
+	mutex       sync.RWMutex
+	options     BrokerOptions
+	seq         uint64
+	subscribers map[string]map[*subscriber]struct{}
+	history     map[string][]historyEntry
+	closed      chan struct{}
+	logger      logging.LoggerInterface
+}
+
+// NewBroker constructs a new SSE broker
+func NewBroker(options *BrokerOptions, logger logging.LoggerInterface) *Broker {
+	var opts BrokerOptions
+	if options != nil {
+		opts = *options
+	}
+	if opts.ChannelParam == "" {
+		opts.ChannelParam = defaultChannelParam
+	}
+	if opts.SubscriberBufferSize <= 0 {
+		opts.SubscriberBufferSize = defaultSubscriberSize
+	}
+	if opts.HistorySize < 0 {
+		opts.HistorySize = 0
+	}
+
+	return &Broker{
+		options:     opts,
+		subscribers: make(map[string]map[*subscriber]struct{}),
+		history:     make(map[string][]historyEntry),
+		closed:      make(chan struct{}),
+		logger:      logger,
+	}
+}
+
+// Publish sends an event to every subscriber of the channel. If the event has no id, a sequential one is assigned
+func (b *Broker) Publish(channel string, event RawEvent) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	select {
+	case <-b.closed:
+		return ErrBrokerClosed
+	default:
+	}
+
+	b.seq++
+	if event.ID() == "" {
+		event = NewRawEvent(strconv.FormatUint(b.seq, 10), event.Event(), event.Data(), event.Retry())
+	}
+
+	entry := historyEntry{seq: b.seq, event: event, payload: EncodeEvent(event)}
+	if b.options.HistorySize > 0 {
+		history := append(b.history[channel], entry)
+		if len(history) > b.options.HistorySize {
+			history = history[len(history)-b.options.HistorySize:]
+		}
+		b.history[channel] = history
+	}
+
+	for sub := range b.subscribers[channel] {
+		select {
+		case sub.out <- entry.payload:
+		default:
+			b.logger.Warning("SSE subscriber too slow. Disconnecting.")
+			b.drop(sub)
+		}
+	}
+	return nil
+}
+
+// Subscribers returns the number of subscribers currently connected to a channel
+func (b *Broker) Subscribers(channel string) int {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	return len(b.subscribers[channel])
+}
+
+// Close disconnects every subscriber and rejects new ones
+func (b *Broker) Close() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	select {
+	case <-b.closed:
+		return
+	default:
+	}
+
+	close(b.closed)
+	for _, subs := range b.subscribers {
+		for sub := range subs {
+			b.drop(sub)
+		}
+	}
+}
+
+// ServeHTTP subscribes the incoming request to the requested channels and streams events until the client disconnects.
+// Both text/event-stream and websocket (one event per text message) connections are supported
+func (b *Broker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	channels := parseChannels(r.URL.Query().Get(b.options.ChannelParam))
+	if len(channels) == 0 {
+		http.Error(w, "no channels requested", http.StatusBadRequest)
+		return
+	}
+
+	sub, replay, err := b.subscribe(channels, r.Header.Get(lastEventIDHeader))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	defer b.unsubscribe(sub)
+
+	var conn brokerConn
+	if isWebSocketUpgrade(r) {
+		conn, err = newWebSocketBrokerConn(w, r)
+	} else {
+		conn, err = newEventStreamBrokerConn(w, r)
+	}
+	if err != nil {
+		b.logger.Debug("Error accepting subscriber: ", err)
+		return
+	}
+	defer conn.close()
+
+	for _, payload := range replay {
+		if err := conn.send(payload); err != nil {
+			return
+		}
+	}
+
+	var keepAlive <-chan time.Time
+	if b.options.KeepAlive > 0 {
+		ticker := time.NewTicker(b.options.KeepAlive)
+		defer ticker.Stop()
+		keepAlive = ticker.C
+	}
+
+	for {
+		select {
+		case <-conn.done():
+			return
+		case <-sub.dropped:
+			return
+		case payload := <-sub.out:
+			if err := conn.send(payload); err != nil {
+				return
+			}
+		case <-keepAlive:
+			if err := conn.send([]byte(keepAliveComment)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// brokerConn abstracts the protocol used to push events to a subscriber
+type brokerConn interface {
+	send(payload []byte) error
+	done() <-chan struct{}
+	close()
+}
+
+type eventStreamBrokerConn struct {
+	writer  http.ResponseWriter
+	flusher http.Flusher
+	request *http.Request
+}
+
+func newEventStreamBrokerConn(w http.ResponseWriter, r *http.Request) (*eventStreamBrokerConn, error) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return nil, errors.New("response writer cannot be flushed")
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	return &eventStreamBrokerConn{writer: w, flusher: flusher, request: r}, nil
+}
+
+func (c *eventStreamBrokerConn) send(payload []byte) error {
+	if _, err := c.writer.Write(payload); err != nil {
+		return err
+	}
+	c.flusher.Flush()
+	return nil
+}
+
+func (c *eventStreamBrokerConn) done() <-chan struct{} { return c.request.Context().Done() }
+
+func (c *eventStreamBrokerConn) close() {}
+
+type webSocketBrokerConn struct {
+	netConn net.Conn
+	conn    *wsConn
+	closed  chan struct{}
+}
+
+func newWebSocketBrokerConn(w http.ResponseWriter, r *http.Request) (*webSocketBrokerConn, error) {
+	netConn, conn, err := acceptWebSocket(w, r)
+	if err != nil {
+		return nil, err
+	}
+
+	toRet := &webSocketBrokerConn{netConn: netConn, conn: conn, closed: make(chan struct{})}
+	go func() {
+		// Incoming messages are discarded. Reading is still needed to answer pings and detect disconnections
+		defer close(toRet.closed)
+		for {
+			if _, err := conn.readMessage(); err != nil {
+				return
+			}
+		}
+	}()
+	return toRet, nil
+}
+
+func (c *webSocketBrokerConn) send(payload []byte) error { return c.conn.writeFrame(wsOpText, payload) }
+
+func (c *webSocketBrokerConn) done() <-chan struct{} { return c.closed }
+
+func (c *webSocketBrokerConn) close() {
+	c.conn.writeFrame(wsOpClose, nil)
+	c.netConn.Close()
+}
+
+func (b *Broker) subscribe(channels []string, lastEventID string) (*subscriber, [][]byte, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	select {
+	case <-b.closed:
+		return nil, nil, ErrBrokerClosed
+	default:
+	}
+
+	sub := &subscriber{
+		channels: channels,
+		out:      make(chan []byte, b.options.SubscriberBufferSize),
+		dropped:  make(chan struct{}),
+	}
+	for _, channel := range channels {
+		if _, ok := b.subscribers[channel]; !ok {
+			b.subscribers[channel] = make(map[*subscriber]struct{})
+		}
+		b.subscribers[channel][sub] = struct{}{}
+	}
+
+	return sub, b.replay(channels, lastEventID), nil
+}
+
+// replay returns the encoded events published to any of the channels after the one identified by lastEventID,
+// in publication order. Nothing is replayed if the id is empty or no longer in the history.
+// Must be called with the lock held
+func (b *Broker) replay(channels []string, lastEventID string) [][]byte {
+	if lastEventID == "" {
+		return nil
+	}
+
+	var since uint64
+	found := false
+	for _, channel := range channels {
+		for _, entry := range b.history[channel] {
+			if entry.event.ID() == lastEventID {
+				since, found = entry.seq, true
+			}
+		}
+	}
+	if !found {
+		return nil
+	}
+
+	var pending []historyEntry
+	for _, channel := range channels {
+		for _, entry := range b.history[channel] {
+			if entry.seq > since {
+				pending = append(pending, entry)
+			}
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].seq < pending[j].seq })
+
+	toRet := make([][]byte, 0, len(pending))
+	for _, entry := range pending {
+		toRet = append(toRet, entry.payload)
+	}
+	return toRet
+}
+
+func (b *Broker) unsubscribe(sub *subscriber) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.drop(sub)
+}
+
+// drop removes the subscriber from every channel and signals its handler to finish. Must be called with the lock held
+func (b *Broker) drop(sub *subscriber) {
+	for _, channel := range sub.channels {
+		delete(b.subscribers[channel], sub)
+		if len(b.subscribers[channel]) == 0 {
+			delete(b.subscribers, channel)
+		}
+	}
+
+	select {
+	case <-sub.dropped:
+	default:
+		close(sub.dropped)
+	}
+}
+
+// EncodeEvent serializes an event in the text/event-stream wire format, splitting multi-line data into several fields
+func EncodeEvent(event RawEvent) []byte {
+	var buffer bytes.Buffer
+	if id := event.ID(); id != "" {
+		writeField(&buffer, sseID, id)
+	}
+	if name := event.Event(); name != "" {
+		writeField(&buffer, sseEvent, name)
+	}
+	if retry := event.Retry(); retry > 0 {
+		writeField(&buffer, sseRetry, strconv.FormatInt(retry, 10))
+	}
+	if data := event.Data(); data != "" {
+		for _, line := range strings.Split(strings.ReplaceAll(data, "\r\n", endOfLineStr), endOfLineStr) {
+			writeField(&buffer, sseData, line)
+		}
+	}
+	buffer.WriteByte(endOfLineChar)
+	return buffer.Bytes()
+}
+
+func writeField(buffer *bytes.Buffer, name string, value string) {
+	buffer.WriteString(name)
+	buffer.WriteString(sseDelimiter)
+	buffer.WriteByte(' ')
+	buffer.WriteString(value)
+	buffer.WriteByte(endOfLineChar)
+}
+
+func parseChannels(raw string) []string {
+	var channels []string
+	seen := make(map[string]struct{})
+	for _, channel := range strings.Split(raw, ",") {
+		channel = strings.TrimSpace(channel)
+		if _, ok := seen[channel]; ok || channel == "" {
+			continue
+		}
+		seen[channel] = struct{}{}
+		channels = append(channels, channel)
+	}
+	return channels
+}
@@ -0,0 +1,132 @@
+package sse
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/splitio/go-toolkit/v5/logging"
+)
+
+func TestEncodeEvent(t *testing.T) {
+	encoded := string(EncodeEvent(NewRawEvent("123", "message", "line1\nline2", 100)))
+	if encoded != "id: 123\nevent: message\nretry: 100\ndata: line1\ndata: line2\n\n" {
+		t.Error("unexpected encoding: ", encoded)
+	}
+
+	builder := NewEventBuilder()
+	for _, line := range strings.SplitAfter(strings.TrimSuffix(encoded, endOfLineStr), endOfLineStr) {
+		if line != "" {
+			builder.AddLine(line)
+		}
+	}
+	e := builder.Build()
+	if e.ID() != "123" || e.Event() != "message" || e.Data() != "line1\nline2" || e.Retry() != 100 {
+		t.Errorf("unexpected decoded event: %+v", e)
+	}
+}
+
+func TestBrokerPublishAndReplay(t *testing.T) {
+	logger := logging.NewLogger(&logging.LoggerOptions{})
+	broker := NewBroker(&BrokerOptions{HistorySize: 10}, logger)
+	ts := httptest.NewServer(broker)
+	defer ts.Close()
+	defer broker.Close()
+
+	client, _ := NewClient(ts.URL, 30, 1, logger)
+	ctx, cancel := context.WithCancel(context.Background())
+	events, _ := client.Stream(ctx, map[string]string{"channel": "ch1,ch2"}, nil)
+
+	waitForSubscribers(t, broker, "ch1", 1)
+	broker.Publish("ch1", NewRawEvent("", "update", "first", 0))
+	broker.Publish("ch3", NewRawEvent("", "update", "ignored", 0))
+	broker.Publish("ch2", NewRawEvent("", "update", "second", 0))
+
+	for _, expected := range []string{"first", "second"} {
+		select {
+		case e := <-events:
+			if e.Data() != expected || e.Event() != "update" {
+				t.Errorf("unexpected event: %+v", e)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("event should have been received")
+		}
+	}
+	cancel()
+	client.lifecycle.AwaitShutdownComplete()
+	waitForSubscribers(t, broker, "ch1", 0)
+
+	broker.Publish("ch2", NewRawEvent("", "update", "third", 0))
+
+	// Reconnect with the id of the first event. Second & third should be replayed
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	events, _ = client.Stream(ctx, map[string]string{"channel": "ch1,ch2"}, map[string]string{"Last-Event-ID": "1"})
+	for _, expected := range []string{"second", "third"} {
+		select {
+		case e := <-events:
+			if e.Data() != expected {
+				t.Errorf("unexpected event: %+v", e)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("event should have been replayed")
+		}
+	}
+}
+
+func TestBrokerKeepAliveAndClose(t *testing.T) {
+	logger := logging.NewLogger(&logging.LoggerOptions{})
+	broker := NewBroker(&BrokerOptions{KeepAlive: 100 * time.Millisecond}, logger)
+	ts := httptest.NewServer(broker)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Error("requests without channels should be rejected. Got: ", resp.StatusCode)
+	}
+
+	resp, err = http.Get(ts.URL + "?channel=ch1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Error("unexpected content type: ", resp.Header.Get("Content-Type"))
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	line, err := reader.ReadString(endOfLineChar)
+	if err != nil || line != ":keepalive\n" {
+		t.Error("a keepalive comment should have been received. Got: ", line, err)
+	}
+
+	broker.Close()
+	for err == nil {
+		_, err = reader.ReadString(endOfLineChar)
+	}
+	if broker.Subscribers("ch1") != 0 {
+		t.Error("no subscribers should remain after closing")
+	}
+	if broker.Publish("ch1", NewRawEvent("", "", "data", 0)) != ErrBrokerClosed {
+		t.Error("publishing on a closed broker should fail")
+	}
+}
+
+func waitForSubscribers(t *testing.T, broker *Broker, channel string, count int) {
+	t.Helper()
+	for attempts := 0; attempts < 100; attempts++ {
+		if broker.Subscribers(channel) == count {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("expected %d subscribers on channel %s", count, channel)
+}
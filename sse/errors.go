@@ -13,6 +13,9 @@ var ErrReadingStream = errors.New("sse channel closed")
 // ErrTimeout is the error to return when keepalive timeout is exceeded
 var ErrTimeout = errors.New("timeout exceeeded")
 
+// ErrLineTooLong is the error to return when an incoming line exceeds the configured maximum size
+var ErrLineTooLong = errors.New("sse line too long")
+
 // ErrConnectionFailed contains a nested error
 type ErrConnectionFailed struct {
 	wrapped error
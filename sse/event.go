@@ -32,6 +32,11 @@ type RawEventImpl struct {
 	retry int64
 }
 
+// NewRawEvent constructs a new event with the supplied properties
+func NewRawEvent(id string, event string, data string, retry int64) *RawEventImpl {
+	return &RawEventImpl{id: id, event: event, data: data, retry: retry}
+}
+
 // ID returns the event id
 func (r *RawEventImpl) ID() string { return r.id }
 
@@ -84,6 +89,7 @@ func (b *EventBuilderImpl) Build() *RawEventImpl {
 	}
 
 	e := &RawEventImpl{}
+	var data []string
 	for _, line := range b.lines {
 		splitted := strings.SplitN(line, sseDelimiter, 2)
 		if len(splitted) != 2 {
@@ -95,13 +101,14 @@ func (b *EventBuilderImpl) Build() *RawEventImpl {
 		case sseID:
 			e.id = strings.TrimSpace(splitted[1])
 		case sseData:
-			e.data = strings.TrimSpace(splitted[1])
+			data = append(data, strings.TrimSpace(splitted[1]))
 		case sseEvent:
 			e.event = strings.TrimSpace(splitted[1])
 		case sseRetry:
 			e.retry, _ = strconv.ParseInt(strings.TrimSpace(splitted[1]), 10, 64)
 		}
 	}
+	e.data = strings.Join(data, endOfLineStr)
 
 	return e
 }
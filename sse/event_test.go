@@ -40,3 +40,13 @@ func TestEventBuilder(t *testing.T) {
 		t.Error("event is an error")
 	}
 }
+
+func TestEventBuilderMultilineData(t *testing.T) {
+	builder := NewEventBuilder()
+	builder.AddLine("data: line1")
+	builder.AddLine("data: line2")
+
+	if e := builder.Build(); e.Data() != "line1\nline2" {
+		t.Error("data lines should be joined. Got: ", e.Data())
+	}
+}
@@ -1,5 +1,11 @@
 package mocks
 
+import (
+	"context"
+
+	"github.com/splitio/go-toolkit/v5/sse"
+)
+
 type RawEventMock struct {
 	IDCall      func() string
 	EventCall   func() string
@@ -32,3 +38,33 @@ func (r *RawEventMock) IsError() bool {
 func (r *RawEventMock) IsEmpty() bool {
 	return r.IsEmptyCall()
 }
+
+type StreamingClientMock struct {
+	DoCall       func(params map[string]string, headers map[string]string, callback func(e sse.RawEvent)) error
+	StreamCall   func(ctx context.Context, params map[string]string, headers map[string]string) (<-chan sse.RawEvent, <-chan error)
+	ShutdownCall func(blocking bool)
+	OnStatusCall func(listener func(sse.StatusEvent))
+	MetricsCall  func() sse.Metrics
+}
+
+func (s *StreamingClientMock) Do(params map[string]string, headers map[string]string, callback func(e sse.RawEvent)) error {
+	return s.DoCall(params, headers, callback)
+}
+
+func (s *StreamingClientMock) Stream(ctx context.Context, params map[string]string, headers map[string]string) (<-chan sse.RawEvent, <-chan error) {
+	return s.StreamCall(ctx, params, headers)
+}
+
+func (s *StreamingClientMock) Shutdown(blocking bool) {
+	s.ShutdownCall(blocking)
+}
+
+func (s *StreamingClientMock) OnStatus(listener func(sse.StatusEvent)) {
+	s.OnStatusCall(listener)
+}
+
+func (s *StreamingClientMock) Metrics() sse.Metrics {
+	return s.MetricsCall()
+}
+
+var _ sse.StreamingClient = (*StreamingClientMock)(nil)
@@ -0,0 +1,122 @@
+package sse
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RecordedLine is a raw line read from an SSE stream along with the time it was received
+type RecordedLine struct {
+	Timestamp time.Time `json:"timestamp"`
+	Line      string    `json:"line"`
+}
+
+// Recorder interface captures raw lines as they're read from the stream
+type Recorder interface {
+	Record(line string, receivedAt time.Time) error
+}
+
+// RecorderImpl writes recorded lines to an io.Writer as JSON, one per line
+type RecorderImpl struct {
+	mutex   sync.Mutex
+	encoder *json.Encoder
+}
+
+// NewRecorder constructs a new recorder that writes to w
+func NewRecorder(w io.Writer) *RecorderImpl {
+	return &RecorderImpl{encoder: json.NewEncoder(w)}
+}
+
+// Record writes the line along with its timestamp
+func (r *RecorderImpl) Record(line string, receivedAt time.Time) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.encoder.Encode(RecordedLine{Timestamp: receivedAt, Line: line})
+}
+
+// ReadRecording parses a recording written by RecorderImpl
+func ReadRecording(r io.Reader) ([]RecordedLine, error) {
+	var lines []RecordedLine
+	decoder := json.NewDecoder(bufio.NewReader(r))
+	for {
+		var line RecordedLine
+		err := decoder.Decode(&line)
+		if err == io.EOF {
+			return lines, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading recorded line %d: %w", len(lines)+1, err)
+		}
+		lines = append(lines, line)
+	}
+}
+
+// Replay feeds the recorded lines through an EventBuilder and calls the callback with every non-empty event, in order.
+// Lines are replayed honoring the original time between them, divided by speed (ie: 2 replays twice as fast).
+// A speed of 0 or lower replays everything without waiting
+func Replay(ctx context.Context, lines []RecordedLine, speed float64, callback func(e RawEvent)) error {
+	eventBuilder := NewEventBuilder()
+	return replayLines(ctx, lines, speed, func(line string) error {
+		if event := feedLine(eventBuilder, line); event != nil && !event.IsEmpty() {
+			callback(event)
+		}
+		return nil
+	})
+}
+
+// ReplayHandler returns an http.Handler that streams the recorded lines to every incoming request, with the
+// same timing semantics as Replay. Once the recording is exhausted, the connection is held open until the client
+// disconnects, so that an sse.Client can be pointed at it as if it were the original server
+func ReplayHandler(lines []RecordedLine, speed float64) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming not supported", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		err := replayLines(r.Context(), lines, speed, func(line string) error {
+			if _, err := io.WriteString(w, line); err != nil {
+				return err
+			}
+			flusher.Flush()
+			return nil
+		})
+		if err != nil {
+			return
+		}
+		<-r.Context().Done()
+	})
+}
+
+func replayLines(ctx context.Context, lines []RecordedLine, speed float64, emit func(line string) error) error {
+	for idx, line := range lines {
+		if idx > 0 && speed > 0 {
+			if wait := time.Duration(float64(line.Timestamp.Sub(lines[idx-1].Timestamp)) / speed); wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return ctx.Err()
+				case <-timer.C:
+				}
+			}
+		}
+
+		if err := emit(line.Line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
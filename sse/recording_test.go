@@ -0,0 +1,95 @@
+package sse
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/splitio/go-toolkit/v5/logging"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	logger := logging.NewLogger(&logging.LoggerOptions{})
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher, _ := w.(http.Flusher)
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprintf(w, "id: 1\nevent: message\ndata: first\n\n")
+		fmt.Fprintf(w, ":keepalive\n\n")
+		flusher.Flush()
+		time.Sleep(200 * time.Millisecond)
+		fmt.Fprintf(w, "id: 2\nevent: message\ndata: second\n\n")
+		flusher.Flush()
+	}))
+	defer ts.Close()
+
+	var buffer bytes.Buffer
+	client, _ := NewClientWithOptions(ts.URL, &ClientOptions{KeepAlive: 30, Recorder: NewRecorder(&buffer)}, logger)
+	client.Do(nil, nil, func(e RawEvent) {})
+
+	lines, err := ReadRecording(&buffer)
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	if len(lines) != 10 {
+		t.Fatal("10 lines should have been recorded. Got: ", len(lines))
+	}
+	if lines[2].Line != "data: first\n" || lines[9].Line != "\n" {
+		t.Error("unexpected recorded lines: ", lines)
+	}
+	if elapsed := lines[6].Timestamp.Sub(lines[0].Timestamp); elapsed < 200*time.Millisecond {
+		t.Error("timestamps should reflect the original timing. Got: ", elapsed)
+	}
+
+	var replayed []RawEvent
+	before := time.Now()
+	err = Replay(context.Background(), lines, 4, func(e RawEvent) { replayed = append(replayed, e) })
+	if err != nil {
+		t.Error("unexpected error: ", err)
+	}
+	if elapsed := time.Since(before); elapsed < 50*time.Millisecond || elapsed > 180*time.Millisecond {
+		t.Error("replay should have been 4x faster than the original. Took: ", elapsed)
+	}
+	if len(replayed) != 2 || replayed[0].Data() != "first" || replayed[1].Data() != "second" || replayed[1].ID() != "2" {
+		t.Error("unexpected replayed events: ", replayed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := Replay(ctx, lines, 1, func(e RawEvent) {}); err != context.Canceled {
+		t.Error("a cancelled replay should return the context error. Got: ", err)
+	}
+}
+
+func TestReplayHandler(t *testing.T) {
+	logger := logging.NewLogger(&logging.LoggerOptions{})
+	now := time.Now()
+	lines := []RecordedLine{
+		{Timestamp: now, Line: "data: first\n"},
+		{Timestamp: now, Line: "\n"},
+		{Timestamp: now.Add(time.Hour), Line: "data: second\n"},
+		{Timestamp: now.Add(time.Hour), Line: "\n"},
+	}
+
+	ts := httptest.NewServer(ReplayHandler(lines, 0))
+	defer ts.Close()
+
+	client, _ := NewClient(ts.URL, 30, 1, logger)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, _ := client.Stream(ctx, nil, nil)
+	for _, expected := range []string{"first", "second"} {
+		select {
+		case e := <-events:
+			if e.Data() != expected {
+				t.Error("unexpected event: ", e)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("event should have been replayed")
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package sse
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrNoDiscriminator is the error passed to the malformed handler when the discriminator field is missing or not a string
+var ErrNoDiscriminator = errors.New("discriminator field missing or not a string")
+
+// Decoder parses the payload of an event into a value of type T
+type Decoder[T any] func(data string) (T, error)
+
+// JSONDecoder is the default decoder, which unmarshals the event data as JSON
+func JSONDecoder[T any](data string) (T, error) {
+	var value T
+	err := json.Unmarshal([]byte(data), &value)
+	return value, err
+}
+
+type route func(e RawEvent) error
+
+// Router dispatches incoming events to typed handlers, selected either by the event type or by the value
+// of a discriminator field in the JSON payload. Its Route method can be used directly as the Do() callback
+type Router struct {
+	mutex           sync.RWMutex
+	discriminator   string
+	byEvent         map[string]route
+	byDiscriminator map[string]route
+	onError         func(e RawEvent)
+	onMalformed     func(e RawEvent, err error)
+	onUnhandled     func(e RawEvent)
+}
+
+// NewRouter constructs a new router. If discriminatorField is not empty, events with no handler registered
+// for their event type are routed according to the value of that (top level, string) field in their data
+func NewRouter(discriminatorField string) *Router {
+	return &Router{
+		discriminator:   discriminatorField,
+		byEvent:         make(map[string]route),
+		byDiscriminator: make(map[string]route),
+		onError:         func(RawEvent) {},
+		onMalformed:     func(RawEvent, error) {},
+		onUnhandled:     func(RawEvent) {},
+	}
+}
+
+// OnError sets the handler for events flagged as errors by the server
+func (r *Router) OnError(handler func(e RawEvent)) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.onError = handler
+}
+
+// OnMalformed sets the handler for events whose payload cannot be decoded
+func (r *Router) OnMalformed(handler func(e RawEvent, err error)) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.onMalformed = handler
+}
+
+// OnUnhandled sets the handler for events that don't match any registered route
+func (r *Router) OnUnhandled(handler func(e RawEvent)) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.onUnhandled = handler
+}
+
+// Route decodes the event and forwards it to the matching handler. Handlers are invoked once the router's lock
+// has been released, so they can safely register new handlers
+func (r *Router) Route(e RawEvent) {
+	r.mutex.RLock()
+	onError, onMalformed, onUnhandled := r.onError, r.onMalformed, r.onUnhandled
+	handler, err := r.resolve(e)
+	r.mutex.RUnlock()
+
+	switch {
+	case e.IsError():
+		onError(e)
+	case err != nil:
+		onMalformed(e, err)
+	case handler == nil:
+		onUnhandled(e)
+	default:
+		if err := handler(e); err != nil {
+			onMalformed(e, err)
+		}
+	}
+}
+
+// resolve returns the route matching the event, or nil if there's none. Must be called with the lock held
+func (r *Router) resolve(e RawEvent) (route, error) {
+	if e.IsError() {
+		return nil, nil
+	}
+
+	if handler, ok := r.byEvent[e.Event()]; ok {
+		return handler, nil
+	}
+
+	if r.discriminator == "" {
+		return nil, nil
+	}
+
+	key, err := r.discriminatorValue(e.Data())
+	if err != nil {
+		return nil, err
+	}
+	return r.byDiscriminator[key], nil
+}
+
+func (r *Router) discriminatorValue(data string) (string, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(data), &fields); err != nil {
+		return "", fmt.Errorf("error parsing event data: %w", err)
+	}
+
+	var value string
+	raw, ok := fields[r.discriminator]
+	if !ok || json.Unmarshal(raw, &value) != nil {
+		return "", ErrNoDiscriminator
+	}
+	return value, nil
+}
+
+func (r *Router) register(routes map[string]route, key string, handler route) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	routes[key] = handler
+}
+
+// Handle registers a handler for events of a specific type, whose data is decoded as JSON
+func Handle[T any](r *Router, eventType string, handler func(e RawEvent, value T)) {
+	HandleWithDecoder(r, eventType, JSONDecoder[T], handler)
+}
+
+// HandleWithDecoder registers a handler for events of a specific type, using a custom decoder
+func HandleWithDecoder[T any](r *Router, eventType string, decoder Decoder[T], handler func(e RawEvent, value T)) {
+	r.register(r.byEvent, eventType, newRoute(decoder, handler))
+}
+
+// HandleDiscriminated registers a handler for events whose discriminator field matches the supplied value,
+// whose data is decoded as JSON
+func HandleDiscriminated[T any](r *Router, value string, handler func(e RawEvent, value T)) {
+	HandleDiscriminatedWithDecoder(r, value, JSONDecoder[T], handler)
+}
+
+// HandleDiscriminatedWithDecoder registers a handler for events whose discriminator field matches the supplied value,
+// using a custom decoder
+func HandleDiscriminatedWithDecoder[T any](r *Router, value string, decoder Decoder[T], handler func(e RawEvent, value T)) {
+	r.register(r.byDiscriminator, value, newRoute(decoder, handler))
+}
+
+func newRoute[T any](decoder Decoder[T], handler func(e RawEvent, value T)) route {
+	return func(e RawEvent) error {
+		value, err := decoder(e.Data())
+		if err != nil {
+			return fmt.Errorf("error decoding event data: %w", err)
+		}
+		handler(e, value)
+		return nil
+	}
+}
@@ -0,0 +1,115 @@
+package sse
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type splitUpdate struct {
+	Type         string `json:"type"`
+	ChangeNumber int64  `json:"changeNumber"`
+}
+
+type occupancy struct {
+	Publishers int `json:"publishers"`
+}
+
+func TestRouterByEventType(t *testing.T) {
+	router := NewRouter("")
+
+	var received []occupancy
+	Handle(router, "occupancy", func(e RawEvent, value occupancy) { received = append(received, value) })
+	HandleWithDecoder(router, "counter", func(data string) (int, error) { return strconv.Atoi(data) }, func(e RawEvent, value int) {
+		if value != 3 {
+			t.Error("unexpected value: ", value)
+		}
+	})
+
+	var errorEvents, unhandled int
+	var malformed []error
+	router.OnError(func(e RawEvent) { errorEvents++ })
+	router.OnUnhandled(func(e RawEvent) { unhandled++ })
+	router.OnMalformed(func(e RawEvent, err error) { malformed = append(malformed, err) })
+
+	router.Route(NewRawEvent("", "occupancy", `{"publishers":2}`, 0))
+	router.Route(NewRawEvent("", "counter", `3`, 0))
+	router.Route(NewRawEvent("", "counter", `three`, 0))
+	router.Route(NewRawEvent("", "occupancy", `{"publishers":`, 0))
+	router.Route(NewRawEvent("", "error", `{"code":40142}`, 0))
+	router.Route(NewRawEvent("", "other", `{}`, 0))
+
+	if len(received) != 1 || received[0].Publishers != 2 {
+		t.Error("unexpected occupancy events: ", received)
+	}
+	if len(malformed) != 2 {
+		t.Error("two events should have been malformed. Got: ", malformed)
+	}
+	if errorEvents != 1 {
+		t.Error("one error event should have been routed. Got: ", errorEvents)
+	}
+	if unhandled != 1 {
+		t.Error("one event should have been unhandled. Got: ", unhandled)
+	}
+}
+
+func TestRouterByDiscriminator(t *testing.T) {
+	router := NewRouter("type")
+
+	var updates []splitUpdate
+	HandleDiscriminated(router, "SPLIT_UPDATE", func(e RawEvent, value splitUpdate) { updates = append(updates, value) })
+
+	var malformed []error
+	var unhandled int
+	router.OnMalformed(func(e RawEvent, err error) { malformed = append(malformed, err) })
+	router.OnUnhandled(func(e RawEvent) { unhandled++ })
+
+	router.Route(NewRawEvent("", "", `{"type":"SPLIT_UPDATE","changeNumber":123}`, 0))
+	router.Route(NewRawEvent("", "", `{"type":"SEGMENT_UPDATE","changeNumber":123}`, 0))
+	router.Route(NewRawEvent("", "", `{"changeNumber":123}`, 0))
+	router.Route(NewRawEvent("", "", `not json`, 0))
+
+	if len(updates) != 1 || updates[0].ChangeNumber != 123 {
+		t.Error("unexpected updates: ", updates)
+	}
+	if unhandled != 1 {
+		t.Error("one event should have been unhandled. Got: ", unhandled)
+	}
+	if len(malformed) != 2 || !errors.Is(malformed[0], ErrNoDiscriminator) {
+		t.Error("unexpected malformed errors: ", malformed)
+	}
+}
+
+func TestRouterReentrantHandlers(t *testing.T) {
+	router := NewRouter("")
+
+	var registered bool
+	Handle(router, "register", func(e RawEvent, value occupancy) {
+		Handle(router, "late", func(e RawEvent, value occupancy) { registered = true })
+	})
+	router.OnUnhandled(func(e RawEvent) {
+		router.OnError(func(e RawEvent) {})
+	})
+	router.OnMalformed(func(e RawEvent, err error) {
+		router.OnMalformed(func(e RawEvent, err error) {})
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		router.Route(NewRawEvent("", "register", `{}`, 0))
+		router.Route(NewRawEvent("", "other", `{}`, 0))
+		router.Route(NewRawEvent("", "register", `{`, 0))
+		router.Route(NewRawEvent("", "late", `{}`, 0))
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("handlers updating the router should not deadlock")
+	}
+	if !registered {
+		t.Error("handlers registered from within a handler should be used")
+	}
+}
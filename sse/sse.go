@@ -3,9 +3,13 @@ package sse
 import (
 	"bufio"
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"net/url"
 	"sync"
 	"time"
 
@@ -16,43 +20,153 @@ import (
 const (
 	endOfLineChar = '\n'
 	endOfLineStr  = "\n"
+
+	eventBufferSize = 1000
+)
+
+// Protocol selects how the client connects to the streaming server
+type Protocol int
+
+// Supported protocols
+const (
+	ProtocolSSE Protocol = iota
+	ProtocolWebSocket
 )
 
+// StreamingClient is the interface implemented by streaming clients, regardless of the underlying protocol
+type StreamingClient interface {
+	Do(params map[string]string, headers map[string]string, callback func(e RawEvent)) error
+	Stream(ctx context.Context, params map[string]string, headers map[string]string) (<-chan RawEvent, <-chan error)
+	Shutdown(blocking bool)
+	OnStatus(listener func(StatusEvent))
+	Metrics() Metrics
+}
+
+// ClientOptions contains the parameters used to customize an SSE Client
+type ClientOptions struct {
+	// Protocol used to connect. Defaults to SSE. When using websockets, every text message is expected to
+	// contain a single event in text/event-stream format, and ws:// and wss:// urls are accepted
+	Protocol Protocol
+	// KeepAlive is the number of seconds without receiving events or keepalive messages after which the stream
+	// is considered dead. Must be higher than 0
+	KeepAlive int
+	// DialTimeout is the number of seconds to wait for the connection to be established. 0 keeps the default
+	DialTimeout int
+	// Transport replaces the default one entirely. Cannot be combined with TLSConfig or Proxy
+	Transport http.RoundTripper
+	// TLSConfig is used when connecting to https urls. Allows setting custom CAs and client certificates
+	TLSConfig *tls.Config
+	// Proxy overrides the default proxy selection, which honors the standard environment variables
+	Proxy func(*http.Request) (*url.URL, error)
+	// HeadersFunc is called on every connection attempt, and its headers are set before the ones passed to Do/Stream
+	HeadersFunc func() (map[string]string, error)
+	// RequestSigner is called on every connection attempt, once the request is complete and just before issuing it
+	RequestSigner func(*http.Request) error
+	// MaxLineSize is the maximum length in bytes of an incoming line (or message, for websockets).
+	// Exceeding it ends the stream. 0 means no limit for lines, and 16MB for websocket messages
+	MaxLineSize int
+	// Recorder, if set, captures every raw line read from the stream
+	Recorder Recorder
+}
+
 // Client struct
 type Client struct {
-	lifecycle lifecycle.Manager
-	url       string
-	client    http.Client
-	timeout   time.Duration
-	logger    logging.LoggerInterface
+	lifecycle     lifecycle.Manager
+	url           string
+	protocol      streamProtocol
+	client        http.Client
+	timeout       time.Duration
+	headersFunc   func() (map[string]string, error)
+	requestSigner func(*http.Request) error
+	maxLineSize   int
+	recorder      Recorder
+	status        statusTracker
+	logger        logging.LoggerInterface
 }
 
 // NewClient creates new SSEClient
 func NewClient(url string, keepAlive int, dialTimeout int, logger logging.LoggerInterface) (*Client, error) {
-	if keepAlive < 1 {
+	return NewClientWithOptions(url, &ClientOptions{KeepAlive: keepAlive, DialTimeout: dialTimeout}, logger)
+}
+
+// NewClientWithOptions creates a new SSEClient with a custom setup
+func NewClientWithOptions(url string, options *ClientOptions, logger logging.LoggerInterface) (*Client, error) {
+	if options == nil {
+		return nil, errors.New("options cannot be nil")
+	}
+	if options.KeepAlive < 1 {
 		return nil, errors.New("keepAlive timeout should be higher than 0")
 	}
-	if dialTimeout < 0 {
-		dialTimeout = 0
+	if options.MaxLineSize < 0 {
+		return nil, errors.New("maxLineSize cannot be negative")
 	}
 
-	transport := http.DefaultTransport.(*http.Transport).Clone()
-	transport.Proxy = http.ProxyFromEnvironment
+	var protocol streamProtocol
+	switch options.Protocol {
+	case ProtocolSSE:
+		protocol = sseProtocol{}
+	case ProtocolWebSocket:
+		protocol = websocketProtocol{}
+		url = websocketToHTTPURL(url)
+	default:
+		return nil, fmt.Errorf("unknown protocol: %d", options.Protocol)
+	}
+
+	transport, err := buildTransport(options)
+	if err != nil {
+		return nil, err
+	}
 
 	client := &Client{
-		url:     url,
-		client:  http.Client{Transport: transport},
-		timeout: time.Duration(keepAlive) * time.Second,
-		logger:  logger,
+		url:           url,
+		protocol:      protocol,
+		client:        http.Client{Transport: transport},
+		timeout:       time.Duration(options.KeepAlive) * time.Second,
+		headersFunc:   options.HeadersFunc,
+		requestSigner: options.RequestSigner,
+		maxLineSize:   options.MaxLineSize,
+		recorder:      options.Recorder,
+		logger:        logger,
 	}
 	client.lifecycle.Setup()
 	return client, nil
 }
 
-func (l *Client) readEvents(in *bufio.Reader, out chan<- RawEvent) {
+func buildTransport(options *ClientOptions) (http.RoundTripper, error) {
+	if options.Transport != nil {
+		if options.TLSConfig != nil || options.Proxy != nil {
+			return nil, errors.New("a custom transport cannot be combined with tls or proxy settings")
+		}
+		return options.Transport, nil
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+	if options.Proxy != nil {
+		transport.Proxy = options.Proxy
+	}
+	if options.TLSConfig != nil {
+		transport.TLSClientConfig = options.TLSConfig.Clone()
+	}
+	if options.Protocol == ProtocolWebSocket {
+		// upgrading the connection requires HTTP/1.1
+		transport.ForceAttemptHTTP2 = false
+		transport.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+	}
+	if options.DialTimeout > 0 {
+		transport.DialContext = (&net.Dialer{
+			Timeout:   time.Duration(options.DialTimeout) * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext
+	}
+	return transport, nil
+}
+
+// readEvents parses the incoming lines into events until the stream fails or ctx is cancelled
+func (l *Client) readEvents(ctx context.Context, next lineSource, out chan<- RawEvent) {
 	eventBuilder := NewEventBuilder()
 	for {
-		line, err := in.ReadString(endOfLineChar)
+		line, err := next()
 		l.logger.Debug("Incoming SSE line: ", line)
 		if err != nil {
 			if l.lifecycle.IsRunning() { // If it's supposed to be running, log an error
@@ -61,39 +175,94 @@ func (l *Client) readEvents(in *bufio.Reader, out chan<- RawEvent) {
 			close(out)
 			return
 		}
-		if line != endOfLineStr {
-			eventBuilder.AddLine(line)
-			continue
-
+		l.status.bytesRead(len(line))
+		if l.recorder != nil {
+			if err := l.recorder.Record(line, time.Now()); err != nil {
+				l.logger.Warning("Error recording SSE line: ", err)
+			}
 		}
-		l.logger.Debug("Building SSE event")
-		if event := eventBuilder.Build(); event != nil {
-			out <- event
+		if event := feedLine(eventBuilder, line); event != nil {
+			select {
+			case out <- event:
+			case <-ctx.Done(): // nobody is consuming events anymore
+				return
+			}
 		}
-		eventBuilder.Reset()
 	}
 }
 
+// feedLine adds a line to the builder, returning the built event when the line marks the end of one
+func feedLine(eventBuilder *EventBuilderImpl, line string) *RawEventImpl {
+	if line != endOfLineStr {
+		eventBuilder.AddLine(line)
+		return nil
+	}
+	defer eventBuilder.Reset()
+	return eventBuilder.Build()
+}
+
 // Do starts streaming
 func (l *Client) Do(params map[string]string, headers map[string]string, callback func(e RawEvent)) error {
+	activeGoroutines := sync.WaitGroup{}
+	return l.do(context.Background(), params, headers, func(e RawEvent) bool {
+		activeGoroutines.Add(1)
+		go func() {
+			defer activeGoroutines.Done()
+			callback(e)
+		}()
+		return true
+	}, activeGoroutines.Wait)
+}
+
+// Stream starts streaming and forwards incoming events through the returned channel, in the order they are received.
+// The event channel is closed when the stream ends. If it ends because of an error, that error is pushed into the error
+// channel before closing it. Cancelling ctx (or calling Shutdown) ends the stream without an error.
+// Events should be consumed promptly, since a blocked consumer eventually stalls the stream.
+func (l *Client) Stream(ctx context.Context, params map[string]string, headers map[string]string) (<-chan RawEvent, <-chan error) {
+	events := make(chan RawEvent, eventBufferSize)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		defer close(events)
+		err := l.do(ctx, params, headers, func(e RawEvent) bool {
+			select {
+			case events <- e:
+			case <-ctx.Done():
+			case <-l.lifecycle.ShutdownRequested():
+				return false
+			}
+			return true
+		}, func() {})
+		if err != nil && ctx.Err() == nil {
+			errs <- err
+		}
+	}()
+	return events, errs
+}
+
+// do performs the request and reads the stream until it ends, is shut down or the context is cancelled,
+// handing every non-empty event to dispatch. dispatch returns false if it consumed a shutdown request while blocked.
+// drain is called before signaling the shutdown as complete
+func (l *Client) do(parent context.Context, params map[string]string, headers map[string]string, dispatch func(e RawEvent) bool, drain func()) error {
 
 	if !l.lifecycle.BeginInitialization() {
 		return ErrNotIdle
 	}
+	l.status.connectionAttempted()
 
-	activeGoroutines := sync.WaitGroup{}
-
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(parent)
 	defer func() {
 		l.logger.Info("SSE streaming exiting")
 		cancel()
-		activeGoroutines.Wait()
+		drain()
 		l.lifecycle.ShutdownComplete()
 	}()
 
 	req, err := l.buildCancellableRequest(ctx, params, headers)
 	if err != nil {
-		return &ErrConnectionFailed{wrapped: fmt.Errorf("error building request: %w", err)}
+		err = &ErrConnectionFailed{wrapped: fmt.Errorf("error building request: %w", err)}
+		l.status.transition(StatusConnectionFailed, ReasonConnectionError, 0, err)
+		return err
 	}
 
 	l.logger.Debug("[GET] ", req.URL.String())
@@ -102,21 +271,31 @@ func (l *Client) Do(params map[string]string, headers map[string]string, callbac
 	resp, err := l.client.Do(req)
 	if err != nil {
 		l.logger.Error("Error performing get: ", req.URL.String(), err.Error())
-		return &ErrConnectionFailed{wrapped: fmt.Errorf("error issuing request: %w", err)}
+		err = &ErrConnectionFailed{wrapped: fmt.Errorf("error issuing request: %w", err)}
+		l.status.transition(StatusConnectionFailed, ReasonConnectionError, 0, err)
+		return err
 	}
-	if resp.StatusCode != 200 {
+	defer resp.Body.Close()
+	if resp.StatusCode != l.streamProtocol().expectedStatus() {
 		l.logger.Error(fmt.Sprintf("GET method: Status Code: %d - %s", resp.StatusCode, resp.Status))
-		return &ErrConnectionFailed{wrapped: fmt.Errorf("sse request status code: %d", resp.StatusCode)}
+		err = &ErrConnectionFailed{wrapped: fmt.Errorf("sse request status code: %d", resp.StatusCode)}
+		l.status.transition(StatusConnectionFailed, ReasonNonOKStatus, resp.StatusCode, err)
+		return err
+	}
+	if err := l.streamProtocol().handshake(resp); err != nil {
+		l.logger.Error("Error performing handshake: ", err.Error())
+		err = &ErrConnectionFailed{wrapped: fmt.Errorf("error performing handshake: %w", err)}
+		l.status.transition(StatusConnectionFailed, ReasonConnectionError, resp.StatusCode, err)
+		return err
 	}
-	defer resp.Body.Close()
 
 	if !l.lifecycle.InitializationComplete() {
 		return nil
 	}
+	l.status.transition(StatusConnected, ReasonNone, resp.StatusCode, nil)
 
-	reader := bufio.NewReader(resp.Body)
-	eventChannel := make(chan RawEvent, 1000)
-	go l.readEvents(reader, eventChannel)
+	eventChannel := make(chan RawEvent, eventBufferSize)
+	go l.readEvents(ctx, l.streamProtocol().lines(resp.Body, l.maxLineSize), eventChannel)
 
 	// Create timeout timer in case SSE dont receive notifications or keepalive messages
 	keepAliveTimer := time.NewTimer(l.timeout)
@@ -126,27 +305,40 @@ func (l *Client) Do(params map[string]string, headers map[string]string, callbac
 		select {
 		case <-l.lifecycle.ShutdownRequested():
 			l.logger.Info("Shutting down listener")
+			l.status.transition(StatusDisconnected, ReasonShutdown, 0, nil)
+			return nil
+		case <-parent.Done():
+			l.logger.Info("SSE context cancelled")
+			l.status.transition(StatusDisconnected, ReasonContextCancelled, 0, parent.Err())
 			return nil
 		case event, ok := <-eventChannel:
 			keepAliveTimer.Reset(l.timeout)
 			if !ok {
+				if parent.Err() != nil {
+					l.status.transition(StatusDisconnected, ReasonContextCancelled, 0, parent.Err())
+					return nil
+				}
 				if l.lifecycle.IsRunning() {
+					l.status.transition(StatusDisconnected, ReasonReadError, 0, ErrReadingStream)
 					return ErrReadingStream
 				}
+				l.status.transition(StatusDisconnected, ReasonShutdown, 0, nil)
 				return nil
 			}
 
+			l.status.eventReceived(event.IsEmpty())
 			if event.IsEmpty() {
 				continue // don't forward empty/comment events
 			}
-			activeGoroutines.Add(1)
-			go func() {
-				defer activeGoroutines.Done()
-				callback(event)
-			}()
+			if !dispatch(event) {
+				l.logger.Info("Shutting down listener")
+				l.status.transition(StatusDisconnected, ReasonShutdown, 0, nil)
+				return nil
+			}
 		case <-keepAliveTimer.C: // Timeout
 			l.logger.Warning("SSE idle timeout.")
 			l.lifecycle.AbnormalShutdown()
+			l.status.transition(StatusDisconnected, ReasonTimeout, 0, ErrTimeout)
 			return ErrTimeout
 		}
 	}
@@ -175,10 +367,79 @@ func (l *Client) buildCancellableRequest(ctx context.Context, params map[string]
 	for key, value := range params {
 		query.Add(key, value)
 	}
+	if l.headersFunc != nil {
+		dynamicHeaders, err := l.headersFunc()
+		if err != nil {
+			return nil, fmt.Errorf("error building headers: %w", err)
+		}
+		for key, value := range dynamicHeaders {
+			req.Header.Set(key, value)
+		}
+	}
 	for key, value := range headers {
 		req.Header.Set(key, value)
 	}
 	req.URL.RawQuery = query.Encode()
-	req.Header.Set("Accept", "text/event-stream")
+	l.streamProtocol().prepareRequest(req)
+
+	if l.requestSigner != nil {
+		if err := l.requestSigner(req); err != nil {
+			return nil, fmt.Errorf("error signing request: %w", err)
+		}
+	}
 	return req, nil
 }
+
+func (l *Client) streamProtocol() streamProtocol {
+	if l.protocol == nil {
+		return sseProtocol{}
+	}
+	return l.protocol
+}
+
+// lineSource returns the next line read from the stream
+type lineSource func() (string, error)
+
+// streamProtocol encapsulates the protocol-specific steps of establishing & reading a stream
+type streamProtocol interface {
+	prepareRequest(req *http.Request)
+	expectedStatus() int
+	handshake(resp *http.Response) error
+	lines(body io.Reader, maxLineSize int) lineSource
+}
+
+type sseProtocol struct{}
+
+func (sseProtocol) prepareRequest(req *http.Request) {
+	req.Header.Set("Accept", "text/event-stream")
+}
+
+func (sseProtocol) expectedStatus() int { return http.StatusOK }
+
+func (sseProtocol) handshake(resp *http.Response) error { return nil }
+
+func (sseProtocol) lines(body io.Reader, maxLineSize int) lineSource {
+	reader := bufio.NewReader(body)
+	return func() (string, error) { return readLine(reader, maxLineSize) }
+}
+
+// readLine reads until the end of line, failing if the line exceeds maxSize bytes (when higher than 0)
+func readLine(in *bufio.Reader, maxSize int) (string, error) {
+	if maxSize <= 0 {
+		return in.ReadString(endOfLineChar)
+	}
+
+	var line []byte
+	for {
+		chunk, err := in.ReadSlice(endOfLineChar)
+		if len(line)+len(chunk) > maxSize {
+			return "", ErrLineTooLong
+		}
+		line = append(line, chunk...)
+		if err != bufio.ErrBufferFull {
+			return string(line), err
+		}
+	}
+}
+
+var _ StreamingClient = (*Client)(nil)
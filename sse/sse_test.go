@@ -1,15 +1,23 @@
 package sse
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"runtime"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/splitio/go-toolkit/v5/logging"
+	"github.com/splitio/go-toolkit/v5/struct/traits/lifecycle"
 )
 
 func TestSSEErrorConnecting(t *testing.T) {
@@ -218,6 +226,201 @@ func TestConnectionEOF(t *testing.T) {
 	mockedClient.Shutdown(true)
 }
 
+func TestSSEStream(t *testing.T) {
+	logger := logging.NewLogger(&logging.LoggerOptions{})
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("some") != "some" {
+			t.Error("It should send header")
+		}
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			t.Error("Unexpected error")
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+
+		for i := 0; i < 3; i++ {
+			fmt.Fprintf(w, "id: %d\ndata: event%d\n\n", i, i)
+			fmt.Fprintf(w, ":keepalive\n\n")
+		}
+		flusher.Flush()
+		<-r.Context().Done()
+	}))
+	defer ts.Close()
+
+	client, _ := NewClient(ts.URL, 30, 1, logger)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, errs := client.Stream(ctx, nil, map[string]string{"some": "some"})
+
+	for i := 0; i < 3; i++ {
+		select {
+		case e := <-events:
+			if e.Data() != fmt.Sprintf("event%d", i) || e.ID() != fmt.Sprintf("%d", i) {
+				t.Error("Unexpected event: ", e)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("event should have been received")
+		}
+	}
+
+	cancel()
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("no more events should be received")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("event channel should have been closed")
+	}
+	if err, ok := <-errs; ok {
+		t.Error("no error should be reported on cancellation. Got: ", err)
+	}
+
+	client.lifecycle.AwaitShutdownComplete()
+	if client.lifecycle.Status() != lifecycle.StatusIdle {
+		t.Error("client should be idle after cancellation")
+	}
+}
+
+func TestSSEStreamShutdownWithBlockedConsumer(t *testing.T) {
+	logger := logging.NewLogger(&logging.LoggerOptions{})
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher, _ := w.(http.Flusher)
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i := 0; i < 2*eventBufferSize+10; i++ { // enough to fill both the events & the internal channel
+			fmt.Fprintf(w, "data: event%d\n\n", i)
+		}
+		flusher.Flush()
+		<-r.Context().Done()
+	}))
+	defer ts.Close()
+
+	client, _ := NewClient(ts.URL, 30, 1, logger)
+	events, _ := client.Stream(context.Background(), nil, nil)
+	for len(events) < eventBufferSize {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		client.Shutdown(true)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("shutdown should not be blocked by a consumer that stopped reading")
+	}
+
+	for attempts := 0; readerRunning(); attempts++ {
+		if attempts == 100 {
+			t.Fatal("the goroutine reading events should exit after shutdown")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func readerRunning() bool {
+	buf := make([]byte, 1<<20)
+	return strings.Contains(string(buf[:runtime.Stack(buf, true)]), "sse.(*Client).readEvents")
+}
+
+func TestSSEStreamError(t *testing.T) {
+	logger := logging.NewLogger(&logging.LoggerOptions{})
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	client, _ := NewClient(ts.URL, 30, 1, logger)
+	events, errs := client.Stream(context.Background(), nil, nil)
+
+	asErrConecting := &ErrConnectionFailed{}
+	if err := <-errs; !errors.As(err, &asErrConecting) {
+		t.Errorf("Unexpected type of error: %+v", err)
+	}
+	if _, ok := <-events; ok {
+		t.Error("event channel should be closed")
+	}
+}
+
+func TestSSEClientOptions(t *testing.T) {
+	logger := logging.NewLogger(&logging.LoggerOptions{})
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token1" {
+			t.Error("It should send the dynamic header. Got: ", r.Header.Get("Authorization"))
+		}
+		if r.Header.Get("Signature") != "signed" {
+			t.Error("It should sign the request")
+		}
+		flusher, _ := w.(http.Flusher)
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprintf(w, "data: %s\n\n", "short")
+		fmt.Fprintf(w, "data: %s\n\n", strings.Repeat("a", 100))
+		flusher.Flush()
+		<-r.Context().Done()
+	}))
+	defer ts.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ts.Certificate())
+
+	var headerCalls, proxyCalls int32
+	client, err := NewClientWithOptions(ts.URL, &ClientOptions{
+		KeepAlive: 30,
+		TLSConfig: &tls.Config{RootCAs: pool},
+		Proxy: func(*http.Request) (*url.URL, error) {
+			atomic.AddInt32(&proxyCalls, 1)
+			return nil, nil
+		},
+		HeadersFunc: func() (map[string]string, error) {
+			return map[string]string{"Authorization": fmt.Sprintf("Bearer token%d", atomic.AddInt32(&headerCalls, 1))}, nil
+		},
+		RequestSigner: func(r *http.Request) error {
+			r.Header.Set("Signature", "signed")
+			return nil
+		},
+		MaxLineSize: 50,
+	}, logger)
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	var events []string
+	err = client.Do(nil, nil, func(e RawEvent) { events = append(events, e.Data()) })
+	if err != ErrReadingStream {
+		t.Error("Exceeding the max line size should end the stream. Got: ", err)
+	}
+	if len(events) != 1 || events[0] != "short" {
+		t.Error("only the short event should be received. Got: ", events)
+	}
+	if atomic.LoadInt32(&headerCalls) != 1 || atomic.LoadInt32(&proxyCalls) != 1 {
+		t.Error("headers & proxy functions should be called once per connection")
+	}
+
+	_, err = NewClientWithOptions(ts.URL, &ClientOptions{KeepAlive: 30, Transport: http.DefaultTransport, TLSConfig: &tls.Config{}}, logger)
+	if err == nil {
+		t.Error("a custom transport should not be accepted alongside tls settings")
+	}
+
+	failing, _ := NewClientWithOptions(ts.URL, &ClientOptions{
+		KeepAlive:   30,
+		Transport:   ts.Client().Transport,
+		HeadersFunc: func() (map[string]string, error) { return nil, errors.New("no token") },
+	}, logger)
+	asErrConecting := &ErrConnectionFailed{}
+	if err := failing.Do(nil, nil, func(e RawEvent) {}); !errors.As(err, &asErrConecting) {
+		t.Errorf("Unexpected type of error: %+v", err)
+	}
+}
+
 /*
 func TestCustom(t *testing.T) {
 	url := `https://streaming.split.io/event-stream`
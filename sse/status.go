@@ -0,0 +1,131 @@
+package sse
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// StatusType indicates the kind of connection status transition
+type StatusType int
+
+// Status transition types
+const (
+	StatusConnected StatusType = iota
+	StatusConnectionFailed
+	StatusDisconnected
+)
+
+// Reason indicates why a connection failed or ended
+type Reason int
+
+// Reasons attached to status transitions
+const (
+	ReasonNone Reason = iota
+	ReasonConnectionError
+	ReasonNonOKStatus
+	ReasonTimeout
+	ReasonReadError
+	ReasonShutdown
+	ReasonContextCancelled
+)
+
+// StatusEvent is pushed to status listeners every time the client connects, fails to connect or disconnects
+type StatusEvent struct {
+	Type       StatusType
+	Reason     Reason
+	StatusCode int
+	Err        error
+	Timestamp  time.Time
+}
+
+// Metrics is a snapshot of the client's connection counters
+type Metrics struct {
+	Connected              bool
+	ConnectionAttempts     int64
+	Reconnections          int64
+	BytesReceived          int64
+	EventsReceived         int64
+	LastKeepAlive          time.Time
+	TimeSinceLastKeepAlive time.Duration
+}
+
+// statusTracker keeps the connection counters & listeners. Its zero value is ready to use
+type statusTracker struct {
+	connected      int32
+	attempts       int64
+	bytesReceived  int64
+	eventsReceived int64
+	lastKeepAlive  int64
+	mutex          sync.RWMutex
+	listeners      []func(StatusEvent)
+}
+
+func (s *statusTracker) connectionAttempted() {
+	atomic.AddInt64(&s.attempts, 1)
+}
+
+func (s *statusTracker) bytesRead(count int) {
+	atomic.AddInt64(&s.bytesReceived, int64(count))
+}
+
+func (s *statusTracker) eventReceived(empty bool) {
+	atomic.StoreInt64(&s.lastKeepAlive, time.Now().UnixNano())
+	if !empty {
+		atomic.AddInt64(&s.eventsReceived, 1)
+	}
+}
+
+func (s *statusTracker) transition(statusType StatusType, reason Reason, statusCode int, err error) {
+	var connected int32
+	if statusType == StatusConnected {
+		connected = 1
+		atomic.StoreInt64(&s.lastKeepAlive, time.Now().UnixNano())
+	}
+	atomic.StoreInt32(&s.connected, connected)
+
+	event := StatusEvent{Type: statusType, Reason: reason, StatusCode: statusCode, Err: err, Timestamp: time.Now()}
+	s.mutex.RLock()
+	listeners := make([]func(StatusEvent), len(s.listeners))
+	copy(listeners, s.listeners)
+	s.mutex.RUnlock()
+
+	for _, listener := range listeners {
+		listener(event)
+	}
+}
+
+func (s *statusTracker) addListener(listener func(StatusEvent)) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.listeners = append(s.listeners, listener)
+}
+
+func (s *statusTracker) snapshot() Metrics {
+	attempts := atomic.LoadInt64(&s.attempts)
+	metrics := Metrics{
+		Connected:          atomic.LoadInt32(&s.connected) == 1,
+		ConnectionAttempts: attempts,
+		BytesReceived:      atomic.LoadInt64(&s.bytesReceived),
+		EventsReceived:     atomic.LoadInt64(&s.eventsReceived),
+	}
+	if attempts > 1 {
+		metrics.Reconnections = attempts - 1
+	}
+	if last := atomic.LoadInt64(&s.lastKeepAlive); last != 0 {
+		metrics.LastKeepAlive = time.Unix(0, last)
+		metrics.TimeSinceLastKeepAlive = time.Since(metrics.LastKeepAlive)
+	}
+	return metrics
+}
+
+// OnStatus registers a listener to be notified of every connection status transition.
+// Listeners are called synchronously from the streaming goroutine, and therefore must not block
+func (l *Client) OnStatus(listener func(StatusEvent)) {
+	l.status.addListener(listener)
+}
+
+// Metrics returns a snapshot of the connection counters
+func (l *Client) Metrics() Metrics {
+	return l.status.snapshot()
+}
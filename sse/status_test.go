@@ -0,0 +1,126 @@
+package sse
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/splitio/go-toolkit/v5/logging"
+)
+
+func TestStatusAndMetrics(t *testing.T) {
+	logger := logging.NewLogger(&logging.LoggerOptions{})
+
+	var mutex sync.Mutex
+	fail := true
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		shouldFail := fail
+		fail = false
+		mutex.Unlock()
+		if shouldFail {
+			http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+			return
+		}
+
+		flusher, _ := w.(http.Flusher)
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprintf(w, "data: some\n\n")
+		fmt.Fprintf(w, ":keepalive\n\n")
+		flusher.Flush()
+	}))
+	defer ts.Close()
+
+	client, _ := NewClient(ts.URL, 30, 1, logger)
+
+	var statuses []StatusEvent
+	client.OnStatus(func(e StatusEvent) { statuses = append(statuses, e) })
+
+	if m := client.Metrics(); m.Connected || m.ConnectionAttempts != 0 || !m.LastKeepAlive.IsZero() {
+		t.Errorf("unexpected initial metrics: %+v", m)
+	}
+
+	client.Do(nil, nil, func(e RawEvent) {})
+	if len(statuses) != 1 || statuses[0].Type != StatusConnectionFailed || statuses[0].Reason != ReasonNonOKStatus || statuses[0].StatusCode != 503 {
+		t.Errorf("unexpected statuses: %+v", statuses)
+	}
+
+	if err := client.Do(nil, nil, func(e RawEvent) {}); err != ErrReadingStream {
+		t.Error("stream should end with a read error. Got: ", err)
+	}
+	if len(statuses) != 3 || statuses[1].Type != StatusConnected || statuses[2].Type != StatusDisconnected || statuses[2].Reason != ReasonReadError {
+		t.Errorf("unexpected statuses: %+v", statuses)
+	}
+
+	m := client.Metrics()
+	if m.Connected || m.ConnectionAttempts != 2 || m.Reconnections != 1 {
+		t.Errorf("unexpected connection metrics: %+v", m)
+	}
+	if m.EventsReceived != 1 || m.BytesReceived != int64(len("data: some\n\n:keepalive\n\n")) {
+		t.Errorf("unexpected traffic metrics: %+v", m)
+	}
+	if m.LastKeepAlive.IsZero() || m.TimeSinceLastKeepAlive > time.Second {
+		t.Errorf("unexpected keepalive metrics: %+v", m)
+	}
+}
+
+func TestStatusListenersReentrancy(t *testing.T) {
+	logger := logging.NewLogger(&logging.LoggerOptions{})
+	client, _ := NewClient("http://localhost", 30, 1, logger)
+
+	var received []string
+	client.OnStatus(func(e StatusEvent) {
+		received = append(received, "first")
+		if len(received) == 1 {
+			client.OnStatus(func(e StatusEvent) { received = append(received, "second") })
+		}
+	})
+
+	done := make(chan struct{})
+	go func() {
+		client.status.transition(StatusConnected, ReasonNone, 200, nil)
+		client.status.transition(StatusDisconnected, ReasonShutdown, 0, nil)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("a listener registering another listener should not deadlock")
+	}
+
+	if len(received) != 3 || received[1] != "first" || received[2] != "second" {
+		t.Error("listeners registered from a listener should be notified of later transitions. Got: ", received)
+	}
+}
+
+func TestStatusTimeout(t *testing.T) {
+	logger := logging.NewLogger(&logging.LoggerOptions{})
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher, _ := w.(http.Flusher)
+		w.Header().Set("Content-Type", "text/event-stream")
+		flusher.Flush()
+		<-r.Context().Done()
+	}))
+	defer ts.Close()
+
+	mockedClient := Client{
+		client:  http.Client{},
+		logger:  logger,
+		timeout: 100 * time.Millisecond,
+		url:     ts.URL,
+	}
+	mockedClient.lifecycle.Setup()
+
+	var statuses []StatusEvent
+	mockedClient.OnStatus(func(e StatusEvent) { statuses = append(statuses, e) })
+	if err := mockedClient.Do(nil, nil, func(e RawEvent) {}); err != ErrTimeout {
+		t.Error("stream should end with a timeout. Got: ", err)
+	}
+	if len(statuses) != 2 || statuses[1].Reason != ReasonTimeout || statuses[1].Err != ErrTimeout {
+		t.Errorf("unexpected statuses: %+v", statuses)
+	}
+}
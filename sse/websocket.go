@@ -0,0 +1,293 @@
+package sse
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const (
+	websocketGUID    = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+	websocketVersion = "13"
+
+	wsOpContinuation = 0x0
+	wsOpText         = 0x1
+	wsOpBinary       = 0x2
+	wsOpClose        = 0x8
+	wsOpPing         = 0x9
+	wsOpPong         = 0xA
+
+	// wsDefaultMaxMessageSize bounds frames & messages when no explicit limit is set, so that a peer can't make us
+	// allocate arbitrary amounts of memory
+	wsDefaultMaxMessageSize = 16 << 20
+)
+
+// ErrWebSocketHandshake is the error to return when the server doesn't properly accept the websocket upgrade
+var ErrWebSocketHandshake = errors.New("invalid websocket handshake")
+
+var errUnmaskedFrame = errors.New("websocket client frames must be masked")
+
+type websocketProtocol struct{}
+
+func (websocketProtocol) prepareRequest(req *http.Request) {
+	key := make([]byte, 16)
+	rand.Read(key)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", websocketVersion)
+	req.Header.Set("Sec-WebSocket-Key", base64.StdEncoding.EncodeToString(key))
+}
+
+func (websocketProtocol) expectedStatus() int { return http.StatusSwitchingProtocols }
+
+func (websocketProtocol) handshake(resp *http.Response) error {
+	if !strings.EqualFold(resp.Header.Get("Upgrade"), "websocket") {
+		return ErrWebSocketHandshake
+	}
+	if resp.Header.Get("Sec-WebSocket-Accept") != websocketAccept(resp.Request.Header.Get("Sec-WebSocket-Key")) {
+		return ErrWebSocketHandshake
+	}
+	if _, ok := resp.Body.(io.ReadWriter); !ok {
+		return fmt.Errorf("%w: connection is not writable", ErrWebSocketHandshake)
+	}
+	return nil
+}
+
+func (websocketProtocol) lines(body io.Reader, maxLineSize int) lineSource {
+	rw, _ := body.(io.ReadWriter) // checked during the handshake
+	conn := &wsConn{reader: bufio.NewReader(rw), writer: rw, mask: true, maxSize: maxLineSize}
+	var pending []string
+	return func() (string, error) {
+		for len(pending) == 0 {
+			message, err := conn.readMessage()
+			if err != nil {
+				return "", err
+			}
+			pending = messageLines(string(message))
+		}
+		line := pending[0]
+		pending = pending[1:]
+		return line, nil
+	}
+}
+
+// messageLines splits a websocket message into lines, making sure it's terminated by a blank one so that
+// every message results in exactly one event
+func messageLines(message string) []string {
+	message = strings.TrimRight(message, "\r\n")
+	if message == "" {
+		return []string{endOfLineStr}
+	}
+	lines := strings.SplitAfter(message+endOfLineStr, endOfLineStr)
+	return append(lines[:len(lines)-1], endOfLineStr)
+}
+
+func websocketToHTTPURL(url string) string {
+	switch {
+	case strings.HasPrefix(url, "ws://"):
+		return "http://" + strings.TrimPrefix(url, "ws://")
+	case strings.HasPrefix(url, "wss://"):
+		return "https://" + strings.TrimPrefix(url, "wss://")
+	}
+	return url
+}
+
+func websocketAccept(key string) string {
+	hash := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
+
+func isWebSocketUpgrade(r *http.Request) bool {
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		return false
+	}
+	for _, token := range strings.Split(r.Header.Get("Connection"), ",") {
+		if strings.EqualFold(strings.TrimSpace(token), "upgrade") {
+			return true
+		}
+	}
+	return false
+}
+
+// wsConn implements the minimal subset of RFC 6455 framing needed to exchange text messages.
+// Client connections must mask outgoing frames, server ones must not
+type wsConn struct {
+	reader      *bufio.Reader
+	writer      io.Writer
+	mask        bool
+	requireMask bool
+	maxSize     int
+	writeMutex  sync.Mutex
+}
+
+// limit returns the maximum size in bytes of a frame or message
+func (c *wsConn) limit() int {
+	if c.maxSize > 0 {
+		return c.maxSize
+	}
+	return wsDefaultMaxMessageSize
+}
+
+// readMessage returns the next data message, transparently answering pings and close frames
+func (c *wsConn) readMessage() ([]byte, error) {
+	var message []byte
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return nil, err
+		}
+
+		switch opcode {
+		case wsOpPing:
+			if err := c.writeFrame(wsOpPong, payload); err != nil {
+				return nil, err
+			}
+			continue
+		case wsOpPong:
+			continue
+		case wsOpClose:
+			c.writeFrame(wsOpClose, payload)
+			return nil, io.EOF
+		case wsOpText, wsOpBinary, wsOpContinuation:
+		default:
+			return nil, fmt.Errorf("unknown websocket opcode: %d", opcode)
+		}
+
+		if len(message)+len(payload) > c.limit() {
+			return nil, ErrLineTooLong
+		}
+		message = append(message, payload...)
+		if fin {
+			return message, nil
+		}
+	}
+}
+
+func (c *wsConn) readFrame() (bool, byte, []byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin := header[0]&0x80 != 0
+	opcode := header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var extended [2]byte
+		if _, err := io.ReadFull(c.reader, extended[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(extended[:]))
+	case 127:
+		var extended [8]byte
+		if _, err := io.ReadFull(c.reader, extended[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(extended[:])
+	}
+	// the limit is an int, so this also guarantees the length can be safely allocated
+	if length > uint64(c.limit()) {
+		return false, 0, nil, ErrLineTooLong
+	}
+	if c.requireMask && !masked {
+		return false, 0, nil, errUnmaskedFrame
+	}
+
+	var key [4]byte
+	if masked {
+		if _, err := io.ReadFull(c.reader, key[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		for idx := range payload {
+			payload[idx] ^= key[idx%4]
+		}
+	}
+	return fin, opcode, payload, nil
+}
+
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	header := make([]byte, 2, 14)
+	header[0] = 0x80 | opcode
+	var maskBit byte
+	if c.mask {
+		maskBit = 0x80
+	}
+
+	length := len(payload)
+	switch {
+	case length < 126:
+		header[1] = maskBit | byte(length)
+	case length <= 0xFFFF:
+		header[1] = maskBit | 126
+		header = append(header, 0, 0)
+		binary.BigEndian.PutUint16(header[2:], uint16(length))
+	default:
+		header[1] = maskBit | 127
+		header = append(header, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(header[2:], uint64(length))
+	}
+
+	data := payload
+	if c.mask {
+		var key [4]byte
+		rand.Read(key[:])
+		header = append(header, key[:]...)
+		data = make([]byte, length)
+		for idx := range payload {
+			data[idx] = payload[idx] ^ key[idx%4]
+		}
+	}
+
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+	if _, err := c.writer.Write(header); err != nil {
+		return err
+	}
+	_, err := c.writer.Write(data)
+	return err
+}
+
+// acceptWebSocket validates the upgrade request and hijacks the underlying connection
+func acceptWebSocket(w http.ResponseWriter, r *http.Request) (net.Conn, *wsConn, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if r.Method != http.MethodGet || key == "" || r.Header.Get("Sec-WebSocket-Version") != websocketVersion {
+		http.Error(w, ErrWebSocketHandshake.Error(), http.StatusBadRequest)
+		return nil, nil, ErrWebSocketHandshake
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "websockets not supported", http.StatusInternalServerError)
+		return nil, nil, errors.New("response writer cannot be hijacked")
+	}
+
+	netConn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error hijacking connection: %w", err)
+	}
+
+	fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n", websocketAccept(key))
+	if err := rw.Flush(); err != nil {
+		netConn.Close()
+		return nil, nil, fmt.Errorf("error writing handshake: %w", err)
+	}
+	return netConn, &wsConn{reader: rw.Reader, writer: netConn, requireMask: true}, nil
+}
@@ -0,0 +1,129 @@
+package sse
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/splitio/go-toolkit/v5/logging"
+)
+
+func TestMessageLines(t *testing.T) {
+	lines := messageLines("id: 1\ndata: some\n")
+	if len(lines) != 3 || lines[0] != "id: 1\n" || lines[1] != "data: some\n" || lines[2] != "\n" {
+		t.Errorf("unexpected lines: %q", lines)
+	}
+
+	if lines := messageLines(":keepalive\n\n"); len(lines) != 2 || lines[1] != "\n" {
+		t.Errorf("unexpected lines: %q", lines)
+	}
+}
+
+func TestWebSocketClient(t *testing.T) {
+	logger := logging.NewLogger(&logging.LoggerOptions{})
+	broker := NewBroker(&BrokerOptions{KeepAlive: 50 * time.Millisecond, HistorySize: 10}, logger)
+	ts := httptest.NewServer(broker)
+	defer ts.Close()
+	defer broker.Close()
+
+	var client StreamingClient
+	client, err := NewClientWithOptions("ws"+strings.TrimPrefix(ts.URL, "http"), &ClientOptions{
+		Protocol:  ProtocolWebSocket,
+		KeepAlive: 1,
+	}, logger)
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	broker.Publish("ch1", NewRawEvent("", "update", "first", 0))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, errs := client.Stream(ctx, map[string]string{"channel": "ch1"}, map[string]string{"Last-Event-ID": "0"})
+	waitForSubscribers(t, broker, "ch1", 1)
+	broker.Publish("ch1", NewRawEvent("", "update", "multi\nline", 0))
+	broker.Publish("ch1", NewRawEvent("", "update", strings.Repeat("a", 70000), 0))
+
+	for _, expected := range []string{"multi\nline", strings.Repeat("a", 70000)} {
+		select {
+		case e := <-events:
+			if e.Data() != expected || e.Event() != "update" {
+				t.Errorf("unexpected event: %s", e.Data()[:10])
+			}
+		case err := <-errs:
+			t.Fatal("unexpected error: ", err)
+		case <-time.After(2 * time.Second):
+			t.Fatal("event should have been received")
+		}
+	}
+
+	// keepalives are sent more frequently than the client timeout
+	time.Sleep(1500 * time.Millisecond)
+	if m := client.Metrics(); !m.Connected || m.EventsReceived != 2 {
+		t.Errorf("unexpected metrics: %+v", m)
+	}
+
+	cancel()
+	if err, ok := <-errs; ok {
+		t.Error("no error should be reported on cancellation. Got: ", err)
+	}
+	waitForSubscribers(t, broker, "ch1", 0)
+}
+
+func TestWebSocketHandshakeFailure(t *testing.T) {
+	logger := logging.NewLogger(&logging.LoggerOptions{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Upgrade", "websocket")
+		w.Header().Set("Connection", "Upgrade")
+		w.Header().Set("Sec-WebSocket-Accept", "wrong")
+		w.WriteHeader(http.StatusSwitchingProtocols)
+	}))
+	defer ts.Close()
+
+	client, _ := NewClientWithOptions(ts.URL, &ClientOptions{Protocol: ProtocolWebSocket, KeepAlive: 30}, logger)
+	err := client.Do(nil, nil, func(e RawEvent) {})
+	if !errors.Is(err, ErrWebSocketHandshake) {
+		t.Error("handshake should fail. Got: ", err)
+	}
+
+	if _, err := NewClientWithOptions(ts.URL, &ClientOptions{Protocol: 5, KeepAlive: 30}, logger); err == nil {
+		t.Error("unknown protocols should be rejected")
+	}
+}
+
+func TestWebSocketFrameLimits(t *testing.T) {
+	oversized := []byte{0x81, 127, 0x7F, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}
+	conn := &wsConn{reader: bufio.NewReader(bytes.NewReader(oversized)), writer: io.Discard}
+	if _, err := conn.readMessage(); err != ErrLineTooLong {
+		t.Error("a frame announcing 2^63 bytes should be rejected. Got: ", err)
+	}
+
+	aboveDefault := []byte{0x81, 127, 0, 0, 0, 0, 0x01, 0, 0, 1}
+	conn = &wsConn{reader: bufio.NewReader(bytes.NewReader(aboveDefault)), writer: io.Discard}
+	if _, err := conn.readMessage(); err != ErrLineTooLong {
+		t.Error("frames above the default limit should be rejected. Got: ", err)
+	}
+
+	fragments := []byte{0x01, 3, 'a', 'b', 'c', 0x80, 3, 'd', 'e', 'f'}
+	conn = &wsConn{reader: bufio.NewReader(bytes.NewReader(fragments)), writer: io.Discard, maxSize: 5}
+	if _, err := conn.readMessage(); err != ErrLineTooLong {
+		t.Error("messages above the configured limit should be rejected. Got: ", err)
+	}
+
+	unmasked := []byte{0x81, 2, 'h', 'i'}
+	conn = &wsConn{reader: bufio.NewReader(bytes.NewReader(unmasked)), writer: io.Discard, requireMask: true}
+	if _, err := conn.readMessage(); err != errUnmaskedFrame {
+		t.Error("unmasked frames should be rejected by servers. Got: ", err)
+	}
+
+	conn = &wsConn{reader: bufio.NewReader(bytes.NewReader(unmasked)), writer: io.Discard}
+	if message, err := conn.readMessage(); err != nil || string(message) != "hi" {
+		t.Error("unmasked frames should be accepted by clients. Got: ", string(message), err)
+	}
+}